@@ -1,16 +1,20 @@
 package controllers
 
 import (
+	"errors"
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
 	"net/http"
-	"project/etc"
+	"project/database/storage"
 	auth "project/etc/jwt"
 	"project/models"
+	"time"
 )
 
 // @Router /v1/login [post]
 // @Summary User login
-// @Description API for user login
+// @Description API for user login with username or e-mail
 // @Tags auth
 // @Accept json
 // @Produce json
@@ -18,6 +22,7 @@ import (
 // @Success 200 {object} models.LoginResponse
 // @Failure 400 {object} models.ResponseError "Invalid input"
 // @Failure 401 {object} models.ResponseError "Unauthorized"
+// @Failure 429 {object} models.ResponseError "Too many failed attempts"
 // @Failure 500 {object} models.ResponseError "Internal Server Error"
 func (h *Controller) LoginUser(c *gin.Context) {
 	var req models.LoginRequest
@@ -29,16 +34,27 @@ func (h *Controller) LoginUser(c *gin.Context) {
 		return
 	}
 
-	user, err := h.store.User().GetByUsername(req.Username)
-	if err != nil {
-		c.JSON(http.StatusUnauthorized, models.ResponseError{
-			ErrorMessage: "User not found",
-			ErrorCode:    "Unauthorized",
+	if h.loginThrottled(c) {
+		return
+	}
+
+	user, err := h.store.User().GetByLogin(req.Username)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusInternalServerError, models.ResponseError{
+			ErrorMessage: "Error while retrieving the user: " + err.Error(),
+			ErrorCode:    "Internal Server Error",
 		})
 		return
 	}
 
-	isPasswordValid, err := etc.CheckPassword(req.Password, user.Password)
+	if err != nil || accountLocked(user) || (user.DeactivatedAt != nil && user.DeactivatedBy != nil) {
+		h.hasher.VerifyDummy(req.Password)
+		h.registerFailedLogin(c, nil)
+		c.JSON(http.StatusUnauthorized, invalidCredentials)
+		return
+	}
+
+	isPasswordValid, rehash, err := h.hasher.Verify(req.Password, user.Password)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, models.ResponseError{
 			ErrorMessage: "Error while checking password: " + err.Error(),
@@ -48,14 +64,127 @@ func (h *Controller) LoginUser(c *gin.Context) {
 	}
 
 	if !isPasswordValid {
+		h.registerFailedLogin(c, user)
+		c.JSON(http.StatusUnauthorized, invalidCredentials)
+		return
+	}
+
+	if rehash {
+		h.upgradePasswordHash(user, req.Password)
+	}
+
+	// With two-factor login the counter is only reset once the second
+	// factor has been checked as well.
+	if user.TOTPEnabledAt == nil {
+		h.resetFailedLogins(user)
+	}
+
+	h.finishPasswordLogin(c, user)
+}
+
+// @Router /v1/token/refresh [post]
+// @Summary Refresh access token
+// @Description API for exchanging a refresh token for a new access and refresh token pair. Every refresh token can be used once; replaying one revokes all tokens issued from the same login
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param token body models.RefreshTokenRequest true "Refresh token"
+// @Success 200 {object} models.LoginResponse
+// @Failure 400 {object} models.ResponseError "Invalid input"
+// @Failure 401 {object} models.ResponseError "Unauthorized"
+// @Failure 500 {object} models.ResponseError "Internal Server Error"
+func (h *Controller) RefreshToken(c *gin.Context) {
+	var req models.RefreshTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ResponseError{
+			ErrorMessage: "Error while binding JSON: " + err.Error(),
+			ErrorCode:    "Bad Request",
+		})
+		return
+	}
+
+	current, err := h.store.RefreshToken().GetByHash(auth.HashToken(req.RefreshToken))
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, models.ResponseError{
+			ErrorMessage: "Invalid refresh token",
+			ErrorCode:    "Unauthorized",
+		})
+		return
+	}
+
+	if current.RevokedAt != nil {
+		h.revokeRefreshFamily(c, current.FamilyID, current.UserID)
+		return
+	}
+
+	if time.Now().After(current.ExpiresAt) {
+		c.JSON(http.StatusUnauthorized, models.ResponseError{
+			ErrorMessage: "Refresh token expired",
+			ErrorCode:    "Unauthorized",
+		})
+		return
+	}
+
+	user, err := h.store.User().Get(models.RequestId{Id: current.UserID})
+	if err != nil {
 		c.JSON(http.StatusUnauthorized, models.ResponseError{
-			ErrorMessage: "Invalid password",
+			ErrorMessage: "User not found",
 			ErrorCode:    "Unauthorized",
 		})
 		return
 	}
 
-	token, err := auth.GenerateToken(user.Id.String(), user.Username)
+	refreshToken, next, err := h.newRefreshToken(user.Id, current.FamilyID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ResponseError{
+			ErrorMessage: "Error while generating refresh token: " + err.Error(),
+			ErrorCode:    "Internal Server Error",
+		})
+		return
+	}
+
+	if err := h.store.RefreshToken().Rotate(current, next); err != nil {
+		if errors.Is(err, storage.ErrRefreshTokenReused) {
+			h.revokeRefreshFamily(c, current.FamilyID, current.UserID)
+			return
+		}
+		c.JSON(http.StatusInternalServerError, models.ResponseError{
+			ErrorMessage: "Error while rotating refresh token: " + err.Error(),
+			ErrorCode:    "Internal Server Error",
+		})
+		return
+	}
+
+	if err := h.store.Session().Seen(current.FamilyID, time.Now()); err != nil {
+		// Logins from before sessions were recorded get one on first use.
+		if !errors.Is(err, gorm.ErrRecordNotFound) || h.recordSession(c, user.Id, current.FamilyID) != nil {
+			c.JSON(http.StatusInternalServerError, models.ResponseError{
+				ErrorMessage: "Error while updating the session",
+				ErrorCode:    "Internal Server Error",
+			})
+			return
+		}
+	}
+
+	h.respondWithTokens(c, user, current.FamilyID, refreshToken)
+}
+
+func (h *Controller) newRefreshToken(userID, familyID uuid.UUID) (string, *models.RefreshToken, error) {
+	token, err := auth.GenerateOpaqueToken()
+	if err != nil {
+		return "", nil, err
+	}
+
+	return token, &models.RefreshToken{
+		UserID:    userID,
+		FamilyID:  familyID,
+		TokenHash: auth.HashToken(token),
+		ExpiresAt: time.Now().Add(h.cfg.RefreshTokenTTL),
+	}, nil
+}
+
+func (h *Controller) respondWithTokens(c *gin.Context, user *models.User, sessionID uuid.UUID, refreshToken string) {
+	resp, err := h.tokenResponse(user, sessionID, refreshToken)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, models.ResponseError{
 			ErrorMessage: "Error while generating token: " + err.Error(),
@@ -64,7 +193,69 @@ func (h *Controller) LoginUser(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, models.LoginResponse{
-		Token: token,
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *Controller) tokenResponse(user *models.User, sessionID uuid.UUID, refreshToken string) (*models.LoginResponse, error) {
+	token, err := h.keys.GenerateToken(user.Id.String(), sessionID.String(), h.cfg.AccessTokenTTL)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.LoginResponse{
+		Token:        token,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int64(h.cfg.AccessTokenTTL.Seconds()),
+	}, nil
+}
+
+// createSession records a session for a fully authenticated user, starts
+// its refresh token family and returns the first token pair of it. Logging
+// in restores an account its owner deactivated.
+func (h *Controller) createSession(c *gin.Context, user *models.User) (*models.LoginResponse, error) {
+	reactivated := user.DeactivatedAt != nil
+	if reactivated {
+		if err := h.store.User().Reactivate(user.Id); err != nil {
+			return nil, err
+		}
+	}
+
+	sessionID := uuid.New()
+	if err := h.recordSession(c, user.Id, sessionID); err != nil {
+		return nil, err
+	}
+
+	refreshToken, refresh, err := h.newRefreshToken(user.Id, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := h.store.RefreshToken().Create(refresh); err != nil {
+		return nil, err
+	}
+
+	resp, err := h.tokenResponse(user, sessionID, refreshToken)
+	if err != nil {
+		return nil, err
+	}
+	resp.Reactivated = reactivated
+	return resp, nil
+}
+
+// revokeRefreshFamily is called when an already rotated refresh token is
+// presented again. Either the client or an attacker holds a stolen copy, and
+// we cannot tell which, so every token from that login is invalidated.
+func (h *Controller) revokeRefreshFamily(c *gin.Context, familyID, userID uuid.UUID) {
+	if err := h.store.Session().Revoke(familyID, userID); err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusInternalServerError, models.ResponseError{
+			ErrorMessage: "Error while revoking refresh tokens: " + err.Error(),
+			ErrorCode:    "Internal Server Error",
+		})
+		return
+	}
+
+	c.JSON(http.StatusUnauthorized, models.ResponseError{
+		ErrorMessage: "Refresh token reuse detected, please log in again",
+		ErrorCode:    "Unauthorized",
 	})
 }
@@ -0,0 +1,119 @@
+package controllers
+
+import (
+	"github.com/google/uuid"
+	"net/http"
+	auth "project/etc/jwt"
+	"project/models"
+	"testing"
+	"time"
+)
+
+func TestRefreshTokenRotation(t *testing.T) {
+	h, store := newTestController(t)
+	user := store.AddUser("alice")
+	sessionID := uuid.New()
+	if err := store.Session().Create(&models.Session{Id: sessionID, UserID: user.Id, LastSeenAt: time.Now()}, &models.LoginEvent{}); err != nil {
+		t.Fatal(err)
+	}
+
+	first, token, err := h.newRefreshToken(user.Id, sessionID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.RefreshToken().Create(token); err != nil {
+		t.Fatal(err)
+	}
+
+	var resp models.LoginResponse
+	code := serve(t, h.RefreshToken, http.MethodPost, jsonBody(t, models.RefreshTokenRequest{RefreshToken: first}), &resp)
+	if code != http.StatusOK {
+		t.Fatalf("refresh: got %d, want %d", code, http.StatusOK)
+	}
+	if resp.Token == "" || resp.RefreshToken == "" || resp.RefreshToken == first {
+		t.Fatalf("refresh did not rotate the tokens: %+v", resp)
+	}
+
+	claims, err := h.keys.ParseToken(resp.Token, auth.TokenAccess)
+	if err != nil {
+		t.Fatalf("access token: %v", err)
+	}
+	if claims.UserID != user.Id.String() || claims.SessionID != sessionID.String() {
+		t.Errorf("access token for user %s session %s, want %s and %s", claims.UserID, claims.SessionID, user.Id, sessionID)
+	}
+
+	second := resp.RefreshToken
+	code = serve(t, h.RefreshToken, http.MethodPost, jsonBody(t, models.RefreshTokenRequest{RefreshToken: second}), &resp)
+	if code != http.StatusOK {
+		t.Fatalf("second refresh: got %d, want %d", code, http.StatusOK)
+	}
+}
+
+func TestRefreshTokenReuseRevokesFamily(t *testing.T) {
+	h, store := newTestController(t)
+	user := store.AddUser("alice")
+	sessionID := uuid.New()
+	if err := store.Session().Create(&models.Session{Id: sessionID, UserID: user.Id, LastSeenAt: time.Now()}, &models.LoginEvent{}); err != nil {
+		t.Fatal(err)
+	}
+
+	stolen, token, err := h.newRefreshToken(user.Id, sessionID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.RefreshToken().Create(token); err != nil {
+		t.Fatal(err)
+	}
+
+	var resp models.LoginResponse
+	if code := serve(t, h.RefreshToken, http.MethodPost, jsonBody(t, models.RefreshTokenRequest{RefreshToken: stolen}), &resp); code != http.StatusOK {
+		t.Fatalf("refresh: got %d, want %d", code, http.StatusOK)
+	}
+	rotated := resp.RefreshToken
+
+	if code := serve(t, h.RefreshToken, http.MethodPost, jsonBody(t, models.RefreshTokenRequest{RefreshToken: stolen}), nil); code != http.StatusUnauthorized {
+		t.Fatalf("replayed refresh token: got %d, want %d", code, http.StatusUnauthorized)
+	}
+
+	if code := serve(t, h.RefreshToken, http.MethodPost, jsonBody(t, models.RefreshTokenRequest{RefreshToken: rotated}), nil); code != http.StatusUnauthorized {
+		t.Errorf("token rotated before the replay: got %d, want %d", code, http.StatusUnauthorized)
+	}
+
+	session, err := store.Session().Get(sessionID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if session.RevokedAt == nil {
+		t.Error("session of the replayed token is not revoked")
+	}
+}
+
+func TestRefreshTokenRejected(t *testing.T) {
+	h, store := newTestController(t)
+	user := store.AddUser("alice")
+
+	expired, token, err := h.newRefreshToken(user.Id, uuid.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+	token.ExpiresAt = time.Now().Add(-time.Minute)
+	if err := store.RefreshToken().Create(token); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{"unknown", "not-a-refresh-token"},
+		{"expired", expired},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code := serve(t, h.RefreshToken, http.MethodPost, jsonBody(t, models.RefreshTokenRequest{RefreshToken: tt.token}), nil)
+			if code != http.StatusUnauthorized {
+				t.Errorf("got %d, want %d", code, http.StatusUnauthorized)
+			}
+		})
+	}
+}
@@ -0,0 +1,168 @@
+package controllers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"net/http"
+	"project/models"
+)
+
+// @Security ApiKeyAuth
+// @Router /v1/users/block/{user_id} [post]
+// @Summary Block a user
+// @Description API for blocking a user. Both users stop seeing each other's tweets and any follow between them is removed
+// @Tags user
+// @Param user_id path string true "User ID to block"
+// @Success 200 {object} models.ResponseSuccess
+// @Failure 400 {object} models.ResponseError "Invalid input"
+// @Failure 401 {object} models.ResponseError "Unauthorized"
+// @Failure 500 {object} models.ResponseError "Internal server error"
+func (h *Controller) BlockUser(c *gin.Context) {
+	userID, targetID, ok := relationshipIDs(c)
+	if !ok {
+		return
+	}
+
+	block := models.Block{
+		ID:        uuid.New(),
+		BlockerID: userID,
+		BlockedID: targetID,
+	}
+
+	if err := h.store.Block().Create(&block); err != nil {
+		c.JSON(http.StatusInternalServerError, models.ResponseError{
+			ErrorMessage: "Error while blocking the user: " + err.Error(),
+			ErrorCode:    "Internal Server Error",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ResponseSuccess{
+		Message: "User blocked successfully",
+	})
+}
+
+// @Security ApiKeyAuth
+// @Router /v1/users/unblock/{user_id} [delete]
+// @Summary Unblock a user
+// @Description API for unblocking a user
+// @Tags user
+// @Param user_id path string true "User ID to unblock"
+// @Success 200 {object} models.ResponseSuccess
+// @Failure 400 {object} models.ResponseError "Invalid input"
+// @Failure 401 {object} models.ResponseError "Unauthorized"
+// @Failure 500 {object} models.ResponseError "Internal server error"
+func (h *Controller) UnblockUser(c *gin.Context) {
+	userID, targetID, ok := relationshipIDs(c)
+	if !ok {
+		return
+	}
+
+	if err := h.store.Block().Delete(userID, targetID); err != nil {
+		c.JSON(http.StatusInternalServerError, models.ResponseError{
+			ErrorMessage: "Error while unblocking the user: " + err.Error(),
+			ErrorCode:    "Internal Server Error",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ResponseSuccess{
+		Message: "User unblocked successfully",
+	})
+}
+
+// @Security ApiKeyAuth
+// @Router /v1/users/mute/{user_id} [post]
+// @Summary Mute a user
+// @Description API for hiding a user's tweets from the current user's timelines without them knowing
+// @Tags user
+// @Param user_id path string true "User ID to mute"
+// @Success 200 {object} models.ResponseSuccess
+// @Failure 400 {object} models.ResponseError "Invalid input"
+// @Failure 401 {object} models.ResponseError "Unauthorized"
+// @Failure 500 {object} models.ResponseError "Internal server error"
+func (h *Controller) MuteUser(c *gin.Context) {
+	userID, targetID, ok := relationshipIDs(c)
+	if !ok {
+		return
+	}
+
+	mute := models.Mute{
+		ID:      uuid.New(),
+		MuterID: userID,
+		MutedID: targetID,
+	}
+
+	if err := h.store.Mute().Create(&mute); err != nil {
+		c.JSON(http.StatusInternalServerError, models.ResponseError{
+			ErrorMessage: "Error while muting the user: " + err.Error(),
+			ErrorCode:    "Internal Server Error",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ResponseSuccess{
+		Message: "User muted successfully",
+	})
+}
+
+// @Security ApiKeyAuth
+// @Router /v1/users/unmute/{user_id} [delete]
+// @Summary Unmute a user
+// @Description API for unmuting a user
+// @Tags user
+// @Param user_id path string true "User ID to unmute"
+// @Success 200 {object} models.ResponseSuccess
+// @Failure 400 {object} models.ResponseError "Invalid input"
+// @Failure 401 {object} models.ResponseError "Unauthorized"
+// @Failure 500 {object} models.ResponseError "Internal server error"
+func (h *Controller) UnmuteUser(c *gin.Context) {
+	userID, targetID, ok := relationshipIDs(c)
+	if !ok {
+		return
+	}
+
+	if err := h.store.Mute().Delete(userID, targetID); err != nil {
+		c.JSON(http.StatusInternalServerError, models.ResponseError{
+			ErrorMessage: "Error while unmuting the user: " + err.Error(),
+			ErrorCode:    "Internal Server Error",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ResponseSuccess{
+		Message: "User unmuted successfully",
+	})
+}
+
+// relationshipIDs returns the current user and the :user_id path parameter,
+// which must be someone else.
+func relationshipIDs(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	targetID, err := uuid.Parse(c.Param("user_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ResponseError{
+			ErrorMessage: "Invalid UUID format from path: " + err.Error(),
+			ErrorCode:    "Bad Request",
+		})
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	userID, err := uuid.Parse(c.GetString("userID"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ResponseError{
+			ErrorMessage: "Invalid UUID format from token: " + err.Error(),
+			ErrorCode:    "Bad Request",
+		})
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	if userID == targetID {
+		c.JSON(http.StatusBadRequest, models.ResponseError{
+			ErrorMessage: "You cannot do this to yourself",
+			ErrorCode:    "Bad Request",
+		})
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	return userID, targetID, true
+}
@@ -2,16 +2,27 @@ package controllers
 
 import (
 	"github.com/gin-gonic/gin"
+	"project/config"
 	"project/database"
+	"project/database/cache"
+	auth "project/etc/jwt"
+	"project/etc/mailer"
+	"project/etc/password"
 	"strconv"
 )
 
 type Controller struct {
-	store database.IStore
+	store   database.IStore
+	cfg     config.Config
+	cache   cache.Cache
+	revoker *auth.Revoker
+	keys    *auth.KeyManager
+	mailer  mailer.Mailer
+	hasher  *password.Hasher
 }
 
-func NewController(store database.IStore) *Controller {
-	return &Controller{store: store}
+func NewController(store database.IStore, cfg config.Config, kv cache.Cache, revoker *auth.Revoker, keys *auth.KeyManager, mail mailer.Mailer) *Controller {
+	return &Controller{store: store, cfg: cfg, cache: kv, revoker: revoker, keys: keys, mailer: mail, hasher: password.NewHasher(cfg.Password)}
 }
 
 func ParsePageQueryParam(c *gin.Context) (uint64, error) {
@@ -0,0 +1,66 @@
+package controllers
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"net/http/httptest"
+	"project/config"
+	"project/database/cache"
+	"project/database/storetest"
+	auth "project/etc/jwt"
+	"project/etc/mailer"
+	"testing"
+	"time"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+func newTestController(t *testing.T) (*Controller, *storetest.Store) {
+	t.Helper()
+
+	store := storetest.New()
+	cfg := config.Load()
+	cfg.JWT = auth.KeyConfig{Algorithm: auth.AlgEdDSA, Rotation: 24 * time.Hour, Grace: time.Hour}
+
+	keys, err := auth.NewKeyManager(store.SigningKey(), cfg.JWT)
+	if err != nil {
+		t.Fatal(err)
+	}
+	kv := cache.NewMemory()
+	revoker := auth.NewRevoker(kv, cfg.AccessTokenTTL)
+	return NewController(store, cfg, kv, revoker, keys, mailer.NewOutbox(t.TempDir())), store
+}
+
+// serve sends a JSON request to handler and decodes the JSON response into
+// out, when out is not nil.
+func serve(t *testing.T, handler gin.HandlerFunc, method, body string, out interface{}) int {
+	t.Helper()
+
+	r := gin.New()
+	r.Handle(method, "/", handler)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(method, "/", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+
+	if out != nil {
+		if err := json.Unmarshal(w.Body.Bytes(), out); err != nil {
+			t.Fatalf("decoding %q: %v", w.Body.String(), err)
+		}
+	}
+	return w.Code
+}
+
+func jsonBody(t *testing.T, v interface{}) string {
+	t.Helper()
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
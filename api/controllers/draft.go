@@ -0,0 +1,307 @@
+package controllers
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"net/http"
+	"project/database/storage"
+	"project/etc/tweettext"
+	"project/models"
+	"strconv"
+)
+
+// @Security ApiKeyAuth
+// @Router /v1/drafts [post]
+// @Summary Create a draft
+// @Description API for saving an unfinished tweet, reply or quote that only the current user can see
+// @Tags draft
+// @Accept json
+// @Produce json
+// @Param draft body models.CreateUpdateDraft true "Draft data"
+// @Success 200 {object} models.ResponseId
+// @Failure 400 {object} models.ResponseError "Invalid input"
+// @Failure 401 {object} models.ResponseError "Unauthorized"
+// @Failure 403 {object} models.ResponseError "Draft limit reached"
+// @Failure 500 {object} models.ResponseError "Internal server error"
+func (h *Controller) CreateDraft(c *gin.Context) {
+	userID, err := uuid.Parse(c.GetString("userID"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ResponseError{
+			ErrorMessage: "Invalid UUID format from token: " + err.Error(),
+			ErrorCode:    "Bad Request",
+		})
+		return
+	}
+
+	draft, ok := h.draftFields(c)
+	if !ok {
+		return
+	}
+	draft.UserID = userID
+
+	id, err := h.store.Draft().Create(draft, h.cfg.DraftLimit)
+	if err != nil {
+		if errors.Is(err, storage.ErrDraftLimit) {
+			c.JSON(http.StatusForbidden, models.ResponseError{
+				ErrorMessage: "You can keep at most " + strconv.Itoa(h.cfg.DraftLimit) + " drafts",
+				ErrorCode:    "Forbidden",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, models.ResponseError{
+			ErrorMessage: "Error while saving the draft: " + err.Error(),
+			ErrorCode:    "Internal Server Error",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ResponseId{Id: id})
+}
+
+// @Security ApiKeyAuth
+// @Router /v1/drafts/{draft_id} [put]
+// @Summary Update a draft
+// @Description API for replacing the contents of one of the current user's drafts
+// @Tags draft
+// @Accept json
+// @Produce json
+// @Param draft_id path string true "Draft ID"
+// @Param draft body models.CreateUpdateDraft true "Draft data"
+// @Success 200 {object} models.ResponseSuccess
+// @Failure 400 {object} models.ResponseError "Invalid input"
+// @Failure 401 {object} models.ResponseError "Unauthorized"
+// @Failure 404 {object} models.ResponseError "Draft not found"
+// @Failure 500 {object} models.ResponseError "Internal server error"
+func (h *Controller) UpdateDraft(c *gin.Context) {
+	id, userID, ok := draftParams(c)
+	if !ok {
+		return
+	}
+
+	draft, ok := h.draftFields(c)
+	if !ok {
+		return
+	}
+	draft.Id = id
+	draft.UserID = userID
+
+	if err := h.store.Draft().Update(draft); err != nil {
+		draftError(c, err, "Error while saving the draft: ")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ResponseSuccess{
+		Message: "Draft updated successfully",
+	})
+}
+
+// @Security ApiKeyAuth
+// @Router /v1/drafts/{draft_id} [delete]
+// @Summary Delete a draft
+// @Description API for deleting one of the current user's drafts
+// @Tags draft
+// @Param draft_id path string true "Draft ID"
+// @Success 200 {object} models.ResponseSuccess
+// @Failure 400 {object} models.ResponseError "Invalid input"
+// @Failure 401 {object} models.ResponseError "Unauthorized"
+// @Failure 404 {object} models.ResponseError "Draft not found"
+// @Failure 500 {object} models.ResponseError "Internal server error"
+func (h *Controller) DeleteDraft(c *gin.Context) {
+	id, userID, ok := draftParams(c)
+	if !ok {
+		return
+	}
+
+	if err := h.store.Draft().Delete(id, userID); err != nil {
+		draftError(c, err, "Error while deleting the draft: ")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ResponseSuccess{
+		Message: "Draft deleted successfully",
+	})
+}
+
+// @Security ApiKeyAuth
+// @Router /v1/drafts [get]
+// @Summary Get drafts
+// @Description API for retrieving the current user's drafts, the most recently saved first
+// @Tags draft
+// @Produce json
+// @Param page query int false "Page number"
+// @Param limit query int false "Number of drafts per page"
+// @Success 200 {object} models.GetDraftsResponse
+// @Failure 400 {object} models.ResponseError "Invalid input"
+// @Failure 401 {object} models.ResponseError "Unauthorized"
+// @Failure 500 {object} models.ResponseError "Internal server error"
+func (h *Controller) GetDrafts(c *gin.Context) {
+	page, err := ParsePageQueryParam(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ResponseError{
+			ErrorMessage: "Invalid page: " + err.Error(),
+			ErrorCode:    "Bad Request",
+		})
+		return
+	}
+
+	limit, err := ParseLimitQueryParam(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ResponseError{
+			ErrorMessage: "Invalid limit: " + err.Error(),
+			ErrorCode:    "Bad Request",
+		})
+		return
+	}
+
+	userID, err := uuid.Parse(c.GetString("userID"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ResponseError{
+			ErrorMessage: "Invalid UUID format from token: " + err.Error(),
+			ErrorCode:    "Bad Request",
+		})
+		return
+	}
+
+	drafts, err := h.store.Draft().GetAllForUser(models.GetDraftsRequest{
+		UserID: userID,
+		Page:   page,
+		Limit:  limit,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ResponseError{
+			ErrorMessage: "Error while retrieving drafts: " + err.Error(),
+			ErrorCode:    "Internal Server Error",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, drafts)
+}
+
+// @Security ApiKeyAuth
+// @Router /v1/drafts/{draft_id}/publish [post]
+// @Summary Publish a draft
+// @Description API for posting a draft as a tweet, reply or quote. The draft is validated like a new tweet and deleted once it is published
+// @Tags draft
+// @Produce json
+// @Param draft_id path string true "Draft ID"
+// @Success 200 {object} models.ResponseId
+// @Failure 400 {object} models.ResponseError "Invalid input"
+// @Failure 401 {object} models.ResponseError "Unauthorized"
+// @Failure 404 {object} models.ResponseError "Draft or the tweet it refers to not found"
+// @Failure 500 {object} models.ResponseError "Internal server error"
+func (h *Controller) PublishDraft(c *gin.Context) {
+	id, userID, ok := draftParams(c)
+	if !ok {
+		return
+	}
+
+	draft, err := h.store.Draft().Get(id, userID)
+	if err != nil {
+		draftError(c, err, "Error while retrieving the draft: ")
+		return
+	}
+
+	tweet := models.Tweet{
+		UserID:        userID,
+		Content:       draft.Content,
+		ImagePath:     draft.ImagePath,
+		VideoPath:     draft.VideoPath,
+		InReplyToID:   draft.InReplyToID,
+		QuotedTweetID: draft.QuotedTweetID,
+		Poll:          draft.Poll,
+	}
+	if !h.prepareTweet(c, &tweet) {
+		return
+	}
+
+	tweetID, err := h.store.Draft().Publish(id, userID, &tweet)
+	if err != nil {
+		draftError(c, err, "Error while publishing the draft: ")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ResponseId{Id: tweetID})
+}
+
+// draftFields binds a draft from the request body. Drafts may be unfinished,
+// but never longer than a tweet may be, both a reply and a quote or with an
+// invalid poll.
+func (h *Controller) draftFields(c *gin.Context) (*models.Draft, bool) {
+	var req models.CreateUpdateDraft
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ResponseError{
+			ErrorMessage: "Error while binding JSON: " + err.Error(),
+			ErrorCode:    "Bad Request",
+		})
+		return nil, false
+	}
+
+	if req.InReplyToID != nil && req.QuotedTweetID != nil {
+		c.JSON(http.StatusBadRequest, models.ResponseError{
+			ErrorMessage: "A tweet cannot both reply to and quote a tweet",
+			ErrorCode:    "Bad Request",
+		})
+		return nil, false
+	}
+
+	content := tweettext.Normalize(req.Content)
+	if err := tweettext.Validate(content, h.cfg.TweetMaxLength); err != nil {
+		c.JSON(http.StatusBadRequest, models.ResponseError{
+			ErrorMessage: "Invalid content: " + err.Error(),
+			ErrorCode:    "Bad Request",
+		})
+		return nil, false
+	}
+
+	if !h.validPoll(c, req.Poll, req.ImagePath != nil || req.VideoPath != nil) {
+		return nil, false
+	}
+
+	return &models.Draft{
+		Content:       content,
+		ImagePath:     req.ImagePath,
+		VideoPath:     req.VideoPath,
+		InReplyToID:   req.InReplyToID,
+		QuotedTweetID: req.QuotedTweetID,
+		Poll:          req.Poll,
+	}, true
+}
+
+func draftParams(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("draft_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ResponseError{
+			ErrorMessage: "Invalid UUID format: " + err.Error(),
+			ErrorCode:    "Bad Request",
+		})
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	userID, err := uuid.Parse(c.GetString("userID"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ResponseError{
+			ErrorMessage: "Invalid UUID format from token: " + err.Error(),
+			ErrorCode:    "Bad Request",
+		})
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	return id, userID, true
+}
+
+func draftError(c *gin.Context, err error, message string) {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, models.ResponseError{
+			ErrorMessage: "Draft not found",
+			ErrorCode:    "Not Found",
+		})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, models.ResponseError{
+		ErrorMessage: message + err.Error(),
+		ErrorCode:    "Internal Server Error",
+	})
+}
@@ -0,0 +1,132 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"project/database/storage"
+	auth "project/etc/jwt"
+	"project/etc/mailer"
+	"project/models"
+	"time"
+)
+
+// @Router /v1/users/email/verify [post]
+// @Summary Verify e-mail address
+// @Description API for confirming an e-mail address with the token sent to it
+// @Tags user
+// @Accept json
+// @Produce json
+// @Param request body models.VerifyEmailRequest true "Verification token"
+// @Success 200 {object} models.ResponseSuccess
+// @Failure 400 {object} models.ResponseError "Invalid input"
+// @Failure 500 {object} models.ResponseError "Internal server error"
+func (h *Controller) VerifyEmail(c *gin.Context) {
+	var req models.VerifyEmailRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ResponseError{
+			ErrorMessage: "Error while binding JSON: " + err.Error(),
+			ErrorCode:    "Bad Request",
+		})
+		return
+	}
+
+	token, err := h.store.UserToken().Consume(models.TokenPurposeEmailVerification, auth.HashToken(req.Token))
+	if err != nil {
+		if errors.Is(err, storage.ErrUserTokenInvalid) {
+			c.JSON(http.StatusBadRequest, models.ResponseError{
+				ErrorMessage: "Verification token is invalid or expired",
+				ErrorCode:    "Bad Request",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, models.ResponseError{
+			ErrorMessage: "Error while checking verification token: " + err.Error(),
+			ErrorCode:    "Internal Server Error",
+		})
+		return
+	}
+
+	if err := h.store.User().MarkEmailVerified(token.UserID); err != nil {
+		c.JSON(http.StatusInternalServerError, models.ResponseError{
+			ErrorMessage: "Error while verifying e-mail: " + err.Error(),
+			ErrorCode:    "Internal Server Error",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ResponseSuccess{
+		Message: "Email verified successfully",
+	})
+}
+
+// @Security ApiKeyAuth
+// @Router /v1/users/email/resend [post]
+// @Summary Resend verification e-mail
+// @Description API for sending a new verification token to the current user's e-mail address
+// @Tags user
+// @Produce json
+// @Success 200 {object} models.ResponseSuccess
+// @Failure 400 {object} models.ResponseError "Invalid input"
+// @Failure 401 {object} models.ResponseError "Unauthorized"
+// @Failure 500 {object} models.ResponseError "Internal server error"
+func (h *Controller) ResendEmailVerification(c *gin.Context) {
+	user, ok := h.currentUser(c)
+	if !ok {
+		return
+	}
+
+	if user.Email == nil {
+		c.JSON(http.StatusBadRequest, models.ResponseError{
+			ErrorMessage: "Account has no e-mail address",
+			ErrorCode:    "Bad Request",
+		})
+		return
+	}
+
+	if user.EmailVerifiedAt != nil {
+		c.JSON(http.StatusBadRequest, models.ResponseError{
+			ErrorMessage: "Email is already verified",
+			ErrorCode:    "Bad Request",
+		})
+		return
+	}
+
+	if err := h.sendEmailVerification(c.Request.Context(), user); err != nil {
+		c.JSON(http.StatusInternalServerError, models.ResponseError{
+			ErrorMessage: "Error while sending verification message: " + err.Error(),
+			ErrorCode:    "Internal Server Error",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ResponseSuccess{
+		Message: "Verification message sent",
+	})
+}
+
+func (h *Controller) sendEmailVerification(ctx context.Context, user *models.User) error {
+	token, err := auth.GenerateOpaqueToken()
+	if err != nil {
+		return err
+	}
+
+	err = h.store.UserToken().Create(&models.UserToken{
+		UserID:    user.Id,
+		Purpose:   models.TokenPurposeEmailVerification,
+		TokenHash: auth.HashToken(token),
+		ExpiresAt: time.Now().Add(h.cfg.EmailVerificationTTL),
+	})
+	if err != nil {
+		return err
+	}
+
+	return h.mailer.Send(ctx, mailer.Message{
+		To:      *user.Email,
+		Subject: "Verify your e-mail address",
+		Body: fmt.Sprintf("Use this token to verify your e-mail address: %s\n\nIt expires in %s.",
+			token, h.cfg.EmailVerificationTTL),
+	})
+}
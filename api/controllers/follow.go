@@ -15,6 +15,7 @@ import (
 // @Param user_id path string true "User ID to follow"
 // @Success 200 {object} models.ResponseSuccess
 // @Failure 400 {object} models.ResponseError "Invalid input"
+// @Failure 403 {object} models.ResponseError "Blocked"
 // @Failure 500 {object} models.ResponseError "Internal server error"
 func (h *Controller) FollowUser(c *gin.Context) {
 	followedID := c.Param("user_id")
@@ -45,6 +46,23 @@ func (h *Controller) FollowUser(c *gin.Context) {
 		})
 	}
 
+	blocked, err := h.store.Block().EitherBlocked(followerID, parsedFollowedID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ResponseError{
+			ErrorMessage: "Error while checking blocks: " + err.Error(),
+			ErrorCode:    "Internal Server Error",
+		})
+		return
+	}
+
+	if blocked {
+		c.JSON(http.StatusForbidden, models.ResponseError{
+			ErrorMessage: "You cannot follow this user",
+			ErrorCode:    "Forbidden",
+		})
+		return
+	}
+
 	isFollowing, err := h.store.Follow().IsFollowing(followerID, parsedFollowedID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, models.ResponseError{
@@ -0,0 +1,102 @@
+package controllers
+
+import (
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"project/etc/tweettext"
+	"project/models"
+)
+
+// @Router /v1/hashtags/{tag}/tweets [get]
+// @Summary Get tweets with a hashtag
+// @Description API for retrieving the tweets using a hashtag, newest first. The hashtag is matched case-insensitively, with or without the leading #. Authentication is optional
+// @Tags hashtag
+// @Produce json
+// @Param tag path string true "Hashtag"
+// @Param page query int false "Page number"
+// @Param limit query int false "Number of tweets per page"
+// @Success 200 {object} models.GetTweetViewsResponse
+// @Failure 400 {object} models.ResponseError "Invalid input"
+// @Failure 401 {object} models.ResponseError "Invalid token"
+// @Failure 500 {object} models.ResponseError "Internal server error"
+func (h *Controller) GetHashtagTweets(c *gin.Context) {
+	tag := tweettext.NormalizeHashtag(c.Param("tag"))
+	if tag == "" {
+		c.JSON(http.StatusBadRequest, models.ResponseError{
+			ErrorMessage: "Hashtag is empty",
+			ErrorCode:    "Bad Request",
+		})
+		return
+	}
+
+	page, err := ParsePageQueryParam(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ResponseError{
+			ErrorMessage: "Invalid page: " + err.Error(),
+			ErrorCode:    "Bad Request",
+		})
+		return
+	}
+
+	limit, err := ParseLimitQueryParam(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ResponseError{
+			ErrorMessage: "Invalid limit: " + err.Error(),
+			ErrorCode:    "Bad Request",
+		})
+		return
+	}
+
+	req := models.GetAllTweetsRequest{
+		Page:     page,
+		Limit:    limit,
+		Hashtag:  tag,
+		ViewerID: viewerID(c),
+	}
+
+	tweets, err := h.store.Tweet().GetAll(req)
+	var resp *models.GetTweetViewsResponse
+	if err == nil {
+		resp, err = h.tweetViewsResponse(req.ViewerID, tweets)
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ResponseError{
+			ErrorMessage: "Error while retrieving tweets: " + err.Error(),
+			ErrorCode:    "Internal Server Error",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// @Router /v1/trends [get]
+// @Summary Get trending hashtags
+// @Description API for retrieving the hashtags used most over the recent past, where recent uses weigh more. The ranking is recomputed periodically
+// @Tags hashtag
+// @Produce json
+// @Param limit query int false "Number of hashtags"
+// @Success 200 {object} models.GetTrendsResponse
+// @Failure 400 {object} models.ResponseError "Invalid input"
+// @Failure 500 {object} models.ResponseError "Internal server error"
+func (h *Controller) GetTrends(c *gin.Context) {
+	limit, err := ParseLimitQueryParam(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ResponseError{
+			ErrorMessage: "Invalid limit: " + err.Error(),
+			ErrorCode:    "Bad Request",
+		})
+		return
+	}
+
+	trends, err := h.store.Hashtag().GetTrends(int(limit))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ResponseError{
+			ErrorMessage: "Error while retrieving trends: " + err.Error(),
+			ErrorCode:    "Internal Server Error",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.GetTrendsResponse{Trends: trends, Count: len(trends)})
+}
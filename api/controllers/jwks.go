@@ -0,0 +1,17 @@
+package controllers
+
+import (
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+// @Router /.well-known/jwks.json [get]
+// @Summary Get the token signing keys
+// @Description Public keys for verifying access tokens issued by this API, matched by the kid header
+// @Tags auth
+// @Produce json
+// @Success 200 {object} auth.JWKSet
+func (h *Controller) JWKS(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, h.keys.JWKS())
+}
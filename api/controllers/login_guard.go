@@ -0,0 +1,206 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"log"
+	"net/http"
+	"project/database/cache"
+	"project/etc/mailer"
+	"project/models"
+	"strconv"
+	"time"
+)
+
+// invalidCredentials is the only answer a failed login gets, whether the
+// account is unknown, locked or the password is wrong, so the response does
+// not reveal which accounts exist.
+var invalidCredentials = models.ResponseError{
+	ErrorMessage: "Invalid username or password",
+	ErrorCode:    "Unauthorized",
+}
+
+func loginIPKey(ip string) string { return "login:failures:ip:" + ip }
+
+// loginThrottled answers with 429 and returns true when the client IP has
+// used up its failed login attempts for the current window.
+func (h *Controller) loginThrottled(c *gin.Context) bool {
+	value, err := h.cache.Get(c.Request.Context(), loginIPKey(c.ClientIP()))
+	if errors.Is(err, cache.ErrNotFound) {
+		return false
+	}
+	if err != nil {
+		log.Printf("Failed to read login throttle: %v", err)
+		return false
+	}
+
+	failures, _ := strconv.ParseInt(value, 10, 64)
+	if failures < h.cfg.LoginIPMaxFailures {
+		return false
+	}
+
+	c.Header("Retry-After", strconv.Itoa(int(h.cfg.LoginIPWindow.Seconds())))
+	c.JSON(http.StatusTooManyRequests, models.ResponseError{
+		ErrorMessage: "Too many failed login attempts, try again later",
+		ErrorCode:    "Too Many Requests",
+	})
+	return true
+}
+
+func accountLocked(user *models.User) bool {
+	return user.LockedUntil != nil && time.Now().Before(*user.LockedUntil)
+}
+
+// registerFailedLogin counts a failed attempt against the client IP and, when
+// user is not nil, against the account, locking it once the threshold is
+// reached. Errors are only logged: the client gets the same answer either way.
+// The account is counted in the background, so a failure on an existing
+// account takes as long to answer as one on an unknown account.
+func (h *Controller) registerFailedLogin(c *gin.Context, user *models.User) {
+	if _, err := h.cache.Incr(c.Request.Context(), loginIPKey(c.ClientIP()), h.cfg.LoginIPWindow); err != nil {
+		log.Printf("Failed to record failed login for ip: %v", err)
+	}
+
+	if user == nil {
+		return
+	}
+
+	go h.registerAccountFailure(*user, c.ClientIP())
+}
+
+// registerAccountFailure counts a failed login against the account and locks
+// it once the threshold is reached, telling the owner by e-mail.
+func (h *Controller) registerAccountFailure(user models.User, ip string) {
+	failures, err := h.store.User().RegisterFailedLogin(user.Id)
+	if err != nil {
+		log.Printf("Failed to record failed login for user %s: %v", user.Id, err)
+		return
+	}
+
+	if failures < h.cfg.LoginLockoutThreshold {
+		return
+	}
+
+	lockedUntil := time.Now().Add(h.lockoutDuration(failures))
+	if err := h.store.User().LockUntil(user.Id, lockedUntil); err != nil {
+		log.Printf("Failed to lock user %s: %v", user.Id, err)
+		return
+	}
+
+	err = h.store.Lockout().Create(&models.LoginLockout{
+		UserID:         user.Id,
+		IP:             ip,
+		FailedAttempts: failures,
+		LockedUntil:    lockedUntil,
+	})
+	if err != nil {
+		log.Printf("Failed to record lockout of user %s: %v", user.Id, err)
+	}
+
+	h.notifyLockout(&user, lockedUntil)
+}
+
+// lockoutDuration doubles the base lock for every failure past the threshold.
+func (h *Controller) lockoutDuration(failures int) time.Duration {
+	shift := failures - h.cfg.LoginLockoutThreshold
+	if shift > 20 {
+		shift = 20
+	}
+
+	duration := h.cfg.LoginLockoutBase << shift
+	if duration > h.cfg.LoginLockoutMax {
+		return h.cfg.LoginLockoutMax
+	}
+	return duration
+}
+
+// notifyLockout tells the owner why their correct password stops working for
+// a while, since the login response itself does not say so.
+func (h *Controller) notifyLockout(user *models.User, until time.Time) {
+	if user.Email == nil {
+		return
+	}
+
+	err := h.mailer.Send(context.Background(), mailer.Message{
+		To:      *user.Email,
+		Subject: "Your account has been locked",
+		Body: fmt.Sprintf("There were too many failed login attempts on your account, so logins are blocked until %s.\n\n"+
+			"If this was not you, consider changing your password once the lock expires.",
+			until.UTC().Format(time.RFC1123)),
+	})
+	if err != nil {
+		log.Printf("Failed to send lockout message: %v", err)
+	}
+}
+
+func (h *Controller) resetFailedLogins(user *models.User) {
+	if user.FailedLogins == 0 && user.LockedUntil == nil {
+		return
+	}
+	if err := h.store.User().ResetFailedLogins(user.Id); err != nil {
+		log.Printf("Failed to reset failed logins of user %s: %v", user.Id, err)
+	}
+}
+
+// @Security ApiKeyAuth
+// @Router /v1/admin/lockouts [get]
+// @Summary Get account lockouts
+// @Description API for reviewing accounts that were locked after too many failed logins
+// @Tags admin
+// @Produce json
+// @Param page query int false "Page number"
+// @Param limit query int false "Number of lockouts per page"
+// @Param user_id query string false "Only lockouts of this user"
+// @Success 200 {object} models.GetAllLockoutsResponse
+// @Failure 400 {object} models.ResponseError "Invalid input"
+// @Failure 403 {object} models.ResponseError "Forbidden"
+// @Failure 500 {object} models.ResponseError "Internal server error"
+func (h *Controller) GetAllLockouts(c *gin.Context) {
+	page, err := ParsePageQueryParam(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ResponseError{
+			ErrorMessage: "Invalid page: " + err.Error(),
+			ErrorCode:    "Bad Request",
+		})
+		return
+	}
+
+	limit, err := ParseLimitQueryParam(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ResponseError{
+			ErrorMessage: "Invalid limit: " + err.Error(),
+			ErrorCode:    "Bad Request",
+		})
+		return
+	}
+
+	req := models.GetAllLockoutsRequest{
+		Page:  page,
+		Limit: limit,
+	}
+
+	if userID := c.Query("user_id"); userID != "" {
+		req.UserID, err = uuid.Parse(userID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, models.ResponseError{
+				ErrorMessage: "Invalid user_id: " + err.Error(),
+				ErrorCode:    "Bad Request",
+			})
+			return
+		}
+	}
+
+	lockouts, err := h.store.Lockout().GetAll(req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ResponseError{
+			ErrorMessage: "Error while retrieving lockouts: " + err.Error(),
+			ErrorCode:    "Internal Server Error",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, lockouts)
+}
@@ -0,0 +1,144 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"io"
+	"net/http"
+	auth "project/etc/jwt"
+	"project/models"
+)
+
+// @Security ApiKeyAuth
+// @Router /v1/logout [post]
+// @Summary Log out
+// @Description API for revoking the current access token and ending its session, including the refresh token issued with it
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param token body models.LogoutRequest false "Refresh token to revoke"
+// @Success 200 {object} models.ResponseSuccess
+// @Failure 400 {object} models.ResponseError "Invalid input"
+// @Failure 401 {object} models.ResponseError "Unauthorized"
+// @Failure 500 {object} models.ResponseError "Internal server error"
+func (h *Controller) Logout(c *gin.Context) {
+	var req models.LogoutRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, models.ResponseError{
+			ErrorMessage: "Error while binding JSON: " + err.Error(),
+			ErrorCode:    "Bad Request",
+		})
+		return
+	}
+
+	claims, ok := claimsFromContext(c)
+	if !ok {
+		return
+	}
+
+	if err := h.revoker.RevokeToken(c.Request.Context(), claims); err != nil {
+		c.JSON(http.StatusInternalServerError, models.ResponseError{
+			ErrorMessage: "Error while revoking token: " + err.Error(),
+			ErrorCode:    "Internal Server Error",
+		})
+		return
+	}
+
+	// Tokens issued before sessions were recorded carry no session id; for
+	// those the refresh token tells which login to end.
+	sessionID, err := uuid.Parse(claims.SessionID)
+	if err != nil && req.RefreshToken != "" {
+		refresh, err := h.store.RefreshToken().GetByHash(auth.HashToken(req.RefreshToken))
+		if err == nil && refresh.UserID.String() == claims.UserID {
+			sessionID = refresh.FamilyID
+		}
+	}
+
+	if sessionID != uuid.Nil {
+		userID, _ := uuid.Parse(claims.UserID)
+		err := h.store.Session().Revoke(sessionID, userID)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusInternalServerError, models.ResponseError{
+				ErrorMessage: "Error while revoking refresh token: " + err.Error(),
+				ErrorCode:    "Internal Server Error",
+			})
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, models.ResponseSuccess{
+		Message: "Logged out successfully",
+	})
+}
+
+// @Security ApiKeyAuth
+// @Router /v1/logout/all [post]
+// @Summary Log out everywhere
+// @Description API for revoking every access and refresh token issued to the current user
+// @Tags auth
+// @Produce json
+// @Success 200 {object} models.ResponseSuccess
+// @Failure 401 {object} models.ResponseError "Unauthorized"
+// @Failure 500 {object} models.ResponseError "Internal server error"
+func (h *Controller) LogoutAll(c *gin.Context) {
+	claims, ok := claimsFromContext(c)
+	if !ok {
+		return
+	}
+
+	userID, err := uuid.Parse(claims.UserID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ResponseError{
+			ErrorMessage: "Invalid UUID format from token: " + err.Error(),
+			ErrorCode:    "Bad Request",
+		})
+		return
+	}
+
+	if err := h.logoutEverywhere(c.Request.Context(), userID); err != nil {
+		c.JSON(http.StatusInternalServerError, models.ResponseError{
+			ErrorMessage: "Error while revoking tokens: " + err.Error(),
+			ErrorCode:    "Internal Server Error",
+		})
+		return
+	}
+
+	// The user-wide cutoff spares tokens issued in the very millisecond
+	// it was set, so revoke the calling token explicitly.
+	if err := h.revoker.RevokeToken(c.Request.Context(), claims); err != nil {
+		c.JSON(http.StatusInternalServerError, models.ResponseError{
+			ErrorMessage: "Error while revoking token: " + err.Error(),
+			ErrorCode:    "Internal Server Error",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ResponseSuccess{
+		Message: "Logged out from all devices successfully",
+	})
+}
+
+// logoutEverywhere ends every session of the user and invalidates every
+// access token issued to them so far.
+func (h *Controller) logoutEverywhere(ctx context.Context, userID uuid.UUID) error {
+	if err := h.store.Session().RevokeAllForUser(userID); err != nil {
+		return err
+	}
+	return h.revoker.RevokeUser(ctx, userID.String())
+}
+
+func claimsFromContext(c *gin.Context) (*auth.Claims, bool) {
+	value, exists := c.Get("claims")
+	claims, ok := value.(*auth.Claims)
+	if !exists || !ok {
+		c.JSON(http.StatusUnauthorized, models.ResponseError{
+			ErrorMessage: "Token claims not found in context",
+			ErrorCode:    "Unauthorized",
+		})
+		return nil, false
+	}
+	return claims, true
+}
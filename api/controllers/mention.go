@@ -0,0 +1,98 @@
+package controllers
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"net/http"
+	"project/models"
+)
+
+// @Router /v1/users/{user_id}/mentions [get]
+// @Summary Get tweets mentioning a user
+// @Description API for retrieving the tweets that mention a user, newest first. Authentication is optional
+// @Tags user
+// @Produce json
+// @Param user_id path string true "User ID"
+// @Param page query int false "Page number"
+// @Param limit query int false "Number of tweets per page"
+// @Success 200 {object} models.GetTweetViewsResponse
+// @Failure 400 {object} models.ResponseError "Invalid input"
+// @Failure 401 {object} models.ResponseError "Invalid token"
+// @Failure 404 {object} models.ResponseError "User not found"
+// @Failure 500 {object} models.ResponseError "Internal server error"
+func (h *Controller) GetUserMentions(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("user_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ResponseError{
+			ErrorMessage: "Invalid UUID format: " + err.Error(),
+			ErrorCode:    "Bad Request",
+		})
+		return
+	}
+
+	page, err := ParsePageQueryParam(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ResponseError{
+			ErrorMessage: "Invalid page: " + err.Error(),
+			ErrorCode:    "Bad Request",
+		})
+		return
+	}
+
+	limit, err := ParseLimitQueryParam(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ResponseError{
+			ErrorMessage: "Invalid limit: " + err.Error(),
+			ErrorCode:    "Bad Request",
+		})
+		return
+	}
+
+	viewer := viewerID(c)
+	user, err := h.store.User().Get(models.RequestId{Id: userID})
+	hidden := errors.Is(err, gorm.ErrRecordNotFound)
+	if err == nil {
+		hidden = user.DeactivatedAt != nil
+		if !hidden && viewer != uuid.Nil {
+			hidden, err = h.store.Block().IsBlocking(userID, viewer)
+		}
+	}
+	if hidden {
+		c.JSON(http.StatusNotFound, models.ResponseError{
+			ErrorMessage: "User not found",
+			ErrorCode:    "Not Found",
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ResponseError{
+			ErrorMessage: "Error while retrieving the user: " + err.Error(),
+			ErrorCode:    "Internal Server Error",
+		})
+		return
+	}
+
+	req := models.GetAllTweetsRequest{
+		Page:            page,
+		Limit:           limit,
+		MentionedUserID: userID,
+		ViewerID:        viewer,
+	}
+
+	tweets, err := h.store.Tweet().GetAll(req)
+	var resp *models.GetTweetViewsResponse
+	if err == nil {
+		resp, err = h.tweetViewsResponse(viewer, tweets)
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ResponseError{
+			ErrorMessage: "Error while retrieving mentions: " + err.Error(),
+			ErrorCode:    "Internal Server Error",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
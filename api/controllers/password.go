@@ -0,0 +1,239 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"log"
+	"net/http"
+	"project/database/storage"
+	auth "project/etc/jwt"
+	"project/etc/mailer"
+	"project/models"
+	"time"
+)
+
+// @Security ApiKeyAuth
+// @Router /v1/users/password [put]
+// @Summary Change password
+// @Description API for changing the password of the current user. All existing sessions are logged out
+// @Tags user
+// @Accept json
+// @Produce json
+// @Param passwords body models.ChangePasswordRequest true "Old and new password"
+// @Success 200 {object} models.ResponseSuccess
+// @Failure 400 {object} models.ResponseError "Invalid input"
+// @Failure 401 {object} models.ResponseError "Unauthorized"
+// @Failure 500 {object} models.ResponseError "Internal server error"
+func (h *Controller) ChangePassword(c *gin.Context) {
+	var req models.ChangePasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ResponseError{
+			ErrorMessage: "Error while binding JSON: " + err.Error(),
+			ErrorCode:    "Bad Request",
+		})
+		return
+	}
+
+	if !h.validatePassword(c, req.NewPassword) {
+		return
+	}
+
+	user, ok := h.currentUser(c)
+	if !ok {
+		return
+	}
+
+	isPasswordValid, _, err := h.hasher.Verify(req.OldPassword, user.Password)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ResponseError{
+			ErrorMessage: "Error while checking password: " + err.Error(),
+			ErrorCode:    "Internal Server Error",
+		})
+		return
+	}
+
+	if !isPasswordValid {
+		c.JSON(http.StatusUnauthorized, models.ResponseError{
+			ErrorMessage: "Invalid password",
+			ErrorCode:    "Unauthorized",
+		})
+		return
+	}
+
+	h.setPassword(c, user.Id, req.NewPassword, "Password changed successfully, please log in again")
+}
+
+// @Router /v1/password/forgot [post]
+// @Summary Request a password reset
+// @Description API for sending a single-use password reset token to the user. Always succeeds so it cannot be used to find out which accounts exist
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body models.ForgotPasswordRequest true "Account to reset"
+// @Success 200 {object} models.ResponseSuccess
+// @Failure 400 {object} models.ResponseError "Invalid input"
+func (h *Controller) ForgotPassword(c *gin.Context) {
+	var req models.ForgotPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ResponseError{
+			ErrorMessage: "Error while binding JSON: " + err.Error(),
+			ErrorCode:    "Bad Request",
+		})
+		return
+	}
+
+	response := models.ResponseSuccess{
+		Message: "If the account exists, a password reset message has been sent",
+	}
+
+	// The reset message is prepared and sent in the background, so the
+	// response takes as long for unknown accounts as for existing ones.
+	user, err := h.store.User().GetByLogin(req.Username)
+	if err == nil && user.Email != nil {
+		go h.sendPasswordReset(user.Id, *user.Email)
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// sendPasswordReset stores a new password reset token of the user and sends
+// it to them. Errors are only logged, the client was answered already.
+func (h *Controller) sendPasswordReset(userID uuid.UUID, email string) {
+	token, err := auth.GenerateOpaqueToken()
+	if err != nil {
+		log.Printf("Failed to generate password reset token: %v", err)
+		return
+	}
+
+	err = h.store.UserToken().Create(&models.UserToken{
+		UserID:    userID,
+		Purpose:   models.TokenPurposePasswordReset,
+		TokenHash: auth.HashToken(token),
+		ExpiresAt: time.Now().Add(h.cfg.PasswordResetTTL),
+	})
+	if err != nil {
+		log.Printf("Failed to save password reset token: %v", err)
+		return
+	}
+
+	msg := mailer.Message{
+		To:      email,
+		Subject: "Reset your password",
+		Body: fmt.Sprintf("Use this token to reset your password: %s\n\nIt expires in %s and can only be used once.",
+			token, h.cfg.PasswordResetTTL),
+	}
+	if err := h.mailer.Send(context.Background(), msg); err != nil {
+		log.Printf("Failed to send password reset message: %v", err)
+	}
+}
+
+// @Router /v1/password/reset [post]
+// @Summary Reset password
+// @Description API for setting a new password with a token from a password reset message. All existing sessions are logged out
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body models.ResetPasswordRequest true "Reset token and new password"
+// @Success 200 {object} models.ResponseSuccess
+// @Failure 400 {object} models.ResponseError "Invalid input"
+// @Failure 500 {object} models.ResponseError "Internal server error"
+func (h *Controller) ResetPassword(c *gin.Context) {
+	var req models.ResetPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ResponseError{
+			ErrorMessage: "Error while binding JSON: " + err.Error(),
+			ErrorCode:    "Bad Request",
+		})
+		return
+	}
+
+	if !h.validatePassword(c, req.NewPassword) {
+		return
+	}
+
+	token, err := h.store.UserToken().Consume(models.TokenPurposePasswordReset, auth.HashToken(req.Token))
+	if err != nil {
+		if errors.Is(err, storage.ErrUserTokenInvalid) {
+			c.JSON(http.StatusBadRequest, models.ResponseError{
+				ErrorMessage: "Reset token is invalid or expired",
+				ErrorCode:    "Bad Request",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, models.ResponseError{
+			ErrorMessage: "Error while checking reset token: " + err.Error(),
+			ErrorCode:    "Internal Server Error",
+		})
+		return
+	}
+
+	h.setPassword(c, token.UserID, req.NewPassword, "Password has been reset successfully")
+}
+
+func (h *Controller) setPassword(c *gin.Context, userID uuid.UUID, password string, message string) {
+	hashPassword, err := h.hasher.Hash(password)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ResponseError{
+			ErrorMessage: "Error while hashing password: " + err.Error(),
+			ErrorCode:    "Internal Server Error",
+		})
+		return
+	}
+
+	if err := h.store.User().UpdatePassword(userID, hashPassword); err != nil {
+		c.JSON(http.StatusInternalServerError, models.ResponseError{
+			ErrorMessage: "Error while updating the password: " + err.Error(),
+			ErrorCode:    "Internal Server Error",
+		})
+		return
+	}
+
+	// Whoever can set the password is the owner, so a lockout caused by
+	// someone guessing it no longer serves a purpose.
+	if err := h.store.User().ResetFailedLogins(userID); err != nil {
+		c.JSON(http.StatusInternalServerError, models.ResponseError{
+			ErrorMessage: "Error while unlocking the account: " + err.Error(),
+			ErrorCode:    "Internal Server Error",
+		})
+		return
+	}
+
+	if err := h.logoutEverywhere(c.Request.Context(), userID); err != nil {
+		c.JSON(http.StatusInternalServerError, models.ResponseError{
+			ErrorMessage: "Error while revoking tokens: " + err.Error(),
+			ErrorCode:    "Internal Server Error",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ResponseSuccess{
+		Message: message,
+	})
+}
+
+// validatePassword checks a new password against the password policy.
+func (h *Controller) validatePassword(c *gin.Context, password string) bool {
+	if err := h.hasher.Validate(password); err != nil {
+		c.JSON(http.StatusBadRequest, models.ResponseError{
+			ErrorMessage: "Password is not allowed: " + err.Error(),
+			ErrorCode:    "Bad Request",
+		})
+		return false
+	}
+	return true
+}
+
+// upgradePasswordHash rehashes a password that was just verified against a
+// legacy hash. Failing to do so does not affect the login.
+func (h *Controller) upgradePasswordHash(user *models.User, password string) {
+	hash, err := h.hasher.Hash(password)
+	if err == nil {
+		err = h.store.User().ReplacePasswordHash(user.Id, user.Password, hash)
+	}
+	if err != nil {
+		log.Printf("Failed to upgrade password hash of user %s: %v", user.Id, err)
+	}
+}
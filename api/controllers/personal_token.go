@@ -0,0 +1,197 @@
+package controllers
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"net/http"
+	auth "project/etc/jwt"
+	"project/models"
+	"time"
+)
+
+// @Security ApiKeyAuth
+// @Router /v1/tokens [post]
+// @Summary Create a personal access token
+// @Description API for creating a scoped token for bots and integrations. The token is only shown in this response
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param token body models.CreatePersonalToken true "Token data"
+// @Success 200 {object} models.CreatePersonalTokenResponse
+// @Failure 400 {object} models.ResponseError "Invalid input"
+// @Failure 401 {object} models.ResponseError "Unauthorized"
+// @Failure 500 {object} models.ResponseError "Internal server error"
+func (h *Controller) CreatePersonalToken(c *gin.Context) {
+	var req models.CreatePersonalToken
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ResponseError{
+			ErrorMessage: "Error while binding JSON: " + err.Error(),
+			ErrorCode:    "Bad Request",
+		})
+		return
+	}
+
+	userID, err := uuid.Parse(c.GetString("userID"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ResponseError{
+			ErrorMessage: "Invalid UUID format from token: " + err.Error(),
+			ErrorCode:    "Bad Request",
+		})
+		return
+	}
+
+	scopes, ok := validScopes(req.Scopes)
+	if !ok {
+		c.JSON(http.StatusBadRequest, models.ResponseError{
+			ErrorMessage: "Scopes must be a non-empty list of known scopes",
+			ErrorCode:    "Bad Request",
+		})
+		return
+	}
+
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		c.JSON(http.StatusBadRequest, models.ResponseError{
+			ErrorMessage: "expires_at must be in the future",
+			ErrorCode:    "Bad Request",
+		})
+		return
+	}
+
+	secret, err := auth.GenerateOpaqueToken()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ResponseError{
+			ErrorMessage: "Error while generating token: " + err.Error(),
+			ErrorCode:    "Internal Server Error",
+		})
+		return
+	}
+
+	plain := auth.PersonalTokenPrefix + secret
+	token := models.PersonalAccessToken{
+		UserID:    userID,
+		Name:      req.Name,
+		Prefix:    plain[:len(auth.PersonalTokenPrefix)+6],
+		TokenHash: auth.HashToken(plain),
+		Scopes:    scopes,
+		ExpiresAt: req.ExpiresAt,
+	}
+
+	if _, err := h.store.PersonalToken().Create(&token); err != nil {
+		c.JSON(http.StatusInternalServerError, models.ResponseError{
+			ErrorMessage: "Error while creating a token: " + err.Error(),
+			ErrorCode:    "Internal Server Error",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.CreatePersonalTokenResponse{
+		Token: plain,
+		Info:  token,
+	})
+}
+
+// @Security ApiKeyAuth
+// @Router /v1/tokens [get]
+// @Summary Get personal access tokens
+// @Description API for listing the personal access tokens of the current user, including revoked ones
+// @Tags auth
+// @Produce json
+// @Success 200 {object} models.GetAllPersonalTokensResponse
+// @Failure 400 {object} models.ResponseError "Invalid input"
+// @Failure 401 {object} models.ResponseError "Unauthorized"
+// @Failure 500 {object} models.ResponseError "Internal server error"
+func (h *Controller) GetAllPersonalTokens(c *gin.Context) {
+	userID, err := uuid.Parse(c.GetString("userID"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ResponseError{
+			ErrorMessage: "Invalid UUID format from token: " + err.Error(),
+			ErrorCode:    "Bad Request",
+		})
+		return
+	}
+
+	tokens, err := h.store.PersonalToken().GetAllForUser(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ResponseError{
+			ErrorMessage: "Error while retrieving tokens: " + err.Error(),
+			ErrorCode:    "Internal Server Error",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.GetAllPersonalTokensResponse{Tokens: tokens})
+}
+
+// @Security ApiKeyAuth
+// @Router /v1/tokens/{token_id} [delete]
+// @Summary Revoke a personal access token
+// @Description API for revoking one of the current user's personal access tokens
+// @Tags auth
+// @Produce json
+// @Param token_id path string true "Token ID"
+// @Success 200 {object} models.ResponseSuccess
+// @Failure 400 {object} models.ResponseError "Invalid input"
+// @Failure 401 {object} models.ResponseError "Unauthorized"
+// @Failure 404 {object} models.ResponseError "Token not found"
+// @Failure 500 {object} models.ResponseError "Internal server error"
+func (h *Controller) RevokePersonalToken(c *gin.Context) {
+	tokenID, err := uuid.Parse(c.Param("token_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ResponseError{
+			ErrorMessage: "Invalid UUID format: " + err.Error(),
+			ErrorCode:    "Bad Request",
+		})
+		return
+	}
+
+	userID, err := uuid.Parse(c.GetString("userID"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ResponseError{
+			ErrorMessage: "Invalid UUID format from token: " + err.Error(),
+			ErrorCode:    "Bad Request",
+		})
+		return
+	}
+
+	if err := h.store.PersonalToken().Revoke(tokenID, userID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, models.ResponseError{
+				ErrorMessage: "Token not found",
+				ErrorCode:    "Not Found",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, models.ResponseError{
+			ErrorMessage: "Error while revoking the token: " + err.Error(),
+			ErrorCode:    "Internal Server Error",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ResponseSuccess{
+		Message: "Token revoked successfully",
+	})
+}
+
+// validScopes removes duplicates and reports whether every scope is known.
+func validScopes(requested []string) ([]string, bool) {
+	known := make(map[string]bool, len(models.Scopes))
+	for _, scope := range models.Scopes {
+		known[scope] = true
+	}
+
+	scopes := make([]string, 0, len(requested))
+	seen := make(map[string]bool, len(requested))
+	for _, scope := range requested {
+		if !known[scope] {
+			return nil, false
+		}
+		if !seen[scope] {
+			seen[scope] = true
+			scopes = append(scopes, scope)
+		}
+	}
+	return scopes, len(scopes) > 0
+}
@@ -0,0 +1,197 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"math"
+	"net/http"
+	"project/database/storage"
+	"project/etc/tweettext"
+	"project/models"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// @Security ApiKeyAuth
+// @Router /v1/tweets/{tweet_id}/poll/votes [post]
+// @Summary Vote in a poll
+// @Description API for voting for an option of a tweet's poll. Every user votes once, and only while the poll is open. Voting in a retweet's poll votes in the original tweet's poll
+// @Tags tweet
+// @Accept json
+// @Produce json
+// @Param tweet_id path string true "Tweet ID"
+// @Param vote body models.PollVoteRequest true "Option to vote for"
+// @Success 200 {object} models.PollView
+// @Failure 400 {object} models.ResponseError "Invalid input"
+// @Failure 401 {object} models.ResponseError "Unauthorized"
+// @Failure 403 {object} models.ResponseError "Poll is closed"
+// @Failure 404 {object} models.ResponseError "Tweet or poll not found"
+// @Failure 409 {object} models.ResponseError "Already voted"
+// @Failure 500 {object} models.ResponseError "Internal server error"
+func (h *Controller) VotePoll(c *gin.Context) {
+	tweetID, err := uuid.Parse(c.Param("tweet_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ResponseError{
+			ErrorMessage: "Invalid UUID format: " + err.Error(),
+			ErrorCode:    "Bad Request",
+		})
+		return
+	}
+
+	userID, err := uuid.Parse(c.GetString("userID"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ResponseError{
+			ErrorMessage: "Invalid UUID format from token: " + err.Error(),
+			ErrorCode:    "Bad Request",
+		})
+		return
+	}
+
+	var req models.PollVoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ResponseError{
+			ErrorMessage: "Error while binding JSON: " + err.Error(),
+			ErrorCode:    "Bad Request",
+		})
+		return
+	}
+
+	tweet, ok := h.sharedTweet(c, userID, tweetID)
+	if !ok {
+		return
+	}
+
+	err = h.store.Poll().Vote(tweet.Id, userID, req.OptionID, time.Now())
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, models.ResponseError{
+			ErrorMessage: "This tweet has no poll",
+			ErrorCode:    "Not Found",
+		})
+		return
+	case errors.Is(err, storage.ErrPollOption):
+		c.JSON(http.StatusBadRequest, models.ResponseError{
+			ErrorMessage: "Invalid option: " + err.Error(),
+			ErrorCode:    "Bad Request",
+		})
+		return
+	case errors.Is(err, storage.ErrPollClosed):
+		c.JSON(http.StatusForbidden, models.ResponseError{
+			ErrorMessage: "This poll is closed",
+			ErrorCode:    "Forbidden",
+		})
+		return
+	case errors.Is(err, storage.ErrAlreadyVoted):
+		c.JSON(http.StatusConflict, models.ResponseError{
+			ErrorMessage: "You have already voted in this poll",
+			ErrorCode:    "Conflict",
+		})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, models.ResponseError{
+			ErrorMessage: "Error while voting: " + err.Error(),
+			ErrorCode:    "Internal Server Error",
+		})
+		return
+	}
+
+	views, err := h.tweetViews(userID, []models.Tweet{*tweet})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ResponseError{
+			ErrorMessage: "Error while retrieving the poll: " + err.Error(),
+			ErrorCode:    "Internal Server Error",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, views[0].Poll)
+}
+
+// validPoll checks a poll to attach to a tweet, which cannot also have media,
+// and answers with an error when it is invalid. Option labels are
+// normalized in place. A nil poll is valid.
+func (h *Controller) validPoll(c *gin.Context, poll *models.CreatePoll, hasMedia bool) bool {
+	if poll == nil {
+		return true
+	}
+
+	invalid := func(message string) bool {
+		c.JSON(http.StatusBadRequest, models.ResponseError{
+			ErrorMessage: "Invalid poll: " + message,
+			ErrorCode:    "Bad Request",
+		})
+		return false
+	}
+
+	if hasMedia {
+		return invalid("a tweet cannot have both media and a poll")
+	}
+	if len(poll.Options) < models.PollMinOptions || len(poll.Options) > models.PollMaxOptions {
+		return invalid(fmt.Sprintf("a poll needs %d to %d options", models.PollMinOptions, models.PollMaxOptions))
+	}
+
+	seen := make(map[string]bool, len(poll.Options))
+	for i, label := range poll.Options {
+		label = strings.TrimSpace(tweettext.Normalize(label))
+		if label == "" {
+			return invalid("options cannot be empty")
+		}
+		if utf8.RuneCountInString(label) > models.PollOptionMaxLength {
+			return invalid(fmt.Sprintf("options can be at most %d characters long", models.PollOptionMaxLength))
+		}
+		if seen[label] {
+			return invalid("options must be different")
+		}
+		seen[label] = true
+		poll.Options[i] = label
+	}
+
+	duration := time.Duration(poll.DurationMinutes) * time.Minute
+	if duration < models.PollMinDuration || duration > models.PollMaxDuration {
+		return invalid(fmt.Sprintf("duration_minutes must be between %d and %d",
+			int(models.PollMinDuration/time.Minute), int(models.PollMaxDuration/time.Minute)))
+	}
+	return true
+}
+
+// pollView presents a poll to the viewer. Results are shown once the viewer
+// has voted or the poll has closed; voted is uuid.Nil when the viewer has
+// not voted.
+func pollView(poll models.Poll, voted uuid.UUID, now time.Time) *models.PollView {
+	v := &models.PollView{
+		Id:      poll.Id,
+		EndsAt:  poll.EndsAt,
+		Closed:  !now.Before(poll.EndsAt),
+		Options: make([]models.PollOptionView, len(poll.Options)),
+	}
+	if voted != uuid.Nil {
+		v.VotedOptionID = &voted
+	}
+
+	showResults := v.Closed || voted != uuid.Nil
+	if showResults {
+		total := poll.VoteCount
+		v.VoteCount = &total
+	}
+
+	for i, option := range poll.Options {
+		v.Options[i] = models.PollOptionView{
+			Id:       option.Id,
+			Position: option.Position,
+			Label:    option.Label,
+		}
+		if showResults {
+			count, percentage := option.VoteCount, 0.0
+			if poll.VoteCount > 0 {
+				percentage = math.Round(float64(count)*1000/float64(poll.VoteCount)) / 10
+			}
+			v.Options[i].VoteCount = &count
+			v.Options[i].Percentage = &percentage
+		}
+	}
+	return v
+}
@@ -0,0 +1,121 @@
+package controllers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"net/http"
+	"net/http/httptest"
+	"project/models"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestValidPoll(t *testing.T) {
+	tests := []struct {
+		name     string
+		poll     *models.CreatePoll
+		hasMedia bool
+		want     bool
+	}{
+		{"no poll", nil, true, true},
+		{"valid", &models.CreatePoll{Options: []string{"yes", "no"}, DurationMinutes: 60}, false, true},
+		{"most options and longest duration", &models.CreatePoll{Options: []string{"a", "b", "c", "d"}, DurationMinutes: 7 * 24 * 60}, false, true},
+		{"with media", &models.CreatePoll{Options: []string{"yes", "no"}, DurationMinutes: 60}, true, false},
+		{"one option", &models.CreatePoll{Options: []string{"yes"}, DurationMinutes: 60}, false, false},
+		{"too many options", &models.CreatePoll{Options: []string{"a", "b", "c", "d", "e"}, DurationMinutes: 60}, false, false},
+		{"blank option", &models.CreatePoll{Options: []string{"yes", "  "}, DurationMinutes: 60}, false, false},
+		{"option too long", &models.CreatePoll{Options: []string{"yes", strings.Repeat("n", models.PollOptionMaxLength+1)}, DurationMinutes: 60}, false, false},
+		{"longest option in code points", &models.CreatePoll{Options: []string{"yes", strings.Repeat("ü", models.PollOptionMaxLength)}, DurationMinutes: 60}, false, true},
+		{"duplicate options after trimming", &models.CreatePoll{Options: []string{"yes", " yes "}, DurationMinutes: 60}, false, false},
+		{"too short", &models.CreatePoll{Options: []string{"yes", "no"}, DurationMinutes: 4}, false, false},
+		{"too long", &models.CreatePoll{Options: []string{"yes", "no"}, DurationMinutes: 7*24*60 + 1}, false, false},
+	}
+
+	var h Controller
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+
+		if got := h.validPoll(c, tt.poll, tt.hasMedia); got != tt.want {
+			t.Errorf("%s: validPoll = %v, want %v", tt.name, got, tt.want)
+		}
+		if !tt.want && w.Code != http.StatusBadRequest {
+			t.Errorf("%s: got %d, want %d", tt.name, w.Code, http.StatusBadRequest)
+		}
+	}
+}
+
+func TestValidPollTrimsOptions(t *testing.T) {
+	poll := &models.CreatePoll{Options: []string{" yes ", "no\t"}, DurationMinutes: 60}
+
+	var h Controller
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	if !h.validPoll(c, poll, false) {
+		t.Fatal("validPoll rejected the poll")
+	}
+	if want := []string{"yes", "no"}; !reflect.DeepEqual(poll.Options, want) {
+		t.Errorf("options = %q, want %q", poll.Options, want)
+	}
+}
+
+func TestPollView(t *testing.T) {
+	now := time.Now()
+	poll := models.Poll{
+		Id:        uuid.New(),
+		EndsAt:    now.Add(time.Hour),
+		VoteCount: 3,
+		Options: []models.PollOption{
+			{Id: uuid.New(), Position: 0, Label: "yes", VoteCount: 2},
+			{Id: uuid.New(), Position: 1, Label: "no", VoteCount: 1},
+		},
+	}
+
+	t.Run("open and not voted", func(t *testing.T) {
+		v := pollView(poll, uuid.Nil, now)
+		if v.Closed || v.VoteCount != nil || v.VotedOptionID != nil {
+			t.Fatalf("got %+v, want an open poll without results", v)
+		}
+		for _, option := range v.Options {
+			if option.VoteCount != nil || option.Percentage != nil {
+				t.Errorf("option %q shows results before voting", option.Label)
+			}
+		}
+	})
+
+	t.Run("voted", func(t *testing.T) {
+		voted := poll.Options[1].Id
+		v := pollView(poll, voted, now)
+		if v.VotedOptionID == nil || *v.VotedOptionID != voted {
+			t.Errorf("voted option = %v, want %s", v.VotedOptionID, voted)
+		}
+		if v.VoteCount == nil || *v.VoteCount != 3 {
+			t.Fatalf("vote count = %v, want 3", v.VoteCount)
+		}
+		for i, want := range []float64{66.7, 33.3} {
+			if got := v.Options[i].Percentage; got == nil || *got != want {
+				t.Errorf("option %q: percentage = %v, want %v", v.Options[i].Label, got, want)
+			}
+		}
+	})
+
+	t.Run("closed", func(t *testing.T) {
+		v := pollView(poll, uuid.Nil, poll.EndsAt)
+		if !v.Closed || v.VoteCount == nil {
+			t.Errorf("got %+v, want a closed poll with results", v)
+		}
+	})
+
+	t.Run("closed without votes", func(t *testing.T) {
+		empty := poll
+		empty.VoteCount = 0
+		empty.Options = []models.PollOption{{Id: uuid.New(), Label: "yes"}, {Id: uuid.New(), Position: 1, Label: "no"}}
+		v := pollView(empty, uuid.Nil, now.Add(2*time.Hour))
+		for _, option := range v.Options {
+			if option.Percentage == nil || *option.Percentage != 0 {
+				t.Errorf("option %q: percentage = %v, want 0", option.Label, option.Percentage)
+			}
+		}
+	})
+}
@@ -0,0 +1,338 @@
+package controllers
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"net/http"
+	"project/models"
+)
+
+// maxConversationSize caps how many tweets of a conversation are loaded.
+const maxConversationSize = 500
+
+const (
+	placeholderDeleted     = "This tweet was deleted"
+	placeholderUnavailable = "This tweet is unavailable"
+)
+
+// @Security ApiKeyAuth
+// @Router /v1/tweets/reply/{tweet_id} [post]
+// @Summary Reply to a tweet
+// @Description API for replying to a tweet. The reply joins the conversation of the tweet; replying to a retweet answers the original tweet
+// @Tags tweet
+// @Accept json
+// @Produce json
+// @Param tweet_id path string true "Tweet ID to reply to"
+// @Param tweet body models.CreateUpdateTweet true "Reply data"
+// @Success 200 {object} models.ResponseId
+// @Failure 400 {object} models.ResponseError "Invalid input"
+// @Failure 401 {object} models.ResponseError "Unauthorized"
+// @Failure 404 {object} models.ResponseError "Tweet not found"
+// @Failure 500 {object} models.ResponseError "Internal server error"
+func (h *Controller) ReplyTweet(c *gin.Context) {
+	parentID, err := uuid.Parse(c.Param("tweet_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ResponseError{
+			ErrorMessage: "Invalid UUID format: " + err.Error(),
+			ErrorCode:    "Bad Request",
+		})
+		return
+	}
+
+	var tweetModel models.CreateUpdateTweet
+	if err := c.ShouldBindJSON(&tweetModel); err != nil {
+		c.JSON(http.StatusBadRequest, models.ResponseError{
+			ErrorMessage: "Error while binding JSON: " + err.Error(),
+			ErrorCode:    "Bad Request",
+		})
+		return
+	}
+
+	if tweetModel.PublishAt != nil {
+		c.JSON(http.StatusBadRequest, models.ResponseError{
+			ErrorMessage: "Only new tweets can be scheduled",
+			ErrorCode:    "Bad Request",
+		})
+		return
+	}
+
+	userID, err := uuid.Parse(c.GetString("userID"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ResponseError{
+			ErrorMessage: "Invalid UUID format from token: " + err.Error(),
+			ErrorCode:    "Bad Request",
+		})
+		return
+	}
+
+	id, ok := h.postTweet(c, &models.Tweet{
+		UserID:      userID,
+		Content:     tweetModel.Content,
+		ImagePath:   tweetModel.ImagePath,
+		VideoPath:   tweetModel.VideoPath,
+		InReplyToID: &parentID,
+		Poll:        tweetModel.Poll,
+	})
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ResponseId{Id: id})
+}
+
+// @Router /v1/tweets/conversation/{tweet_id} [get]
+// @Summary Get a conversation
+// @Description API for retrieving the whole conversation a tweet belongs to, either as a tree of replies or as a flat list in reading order with the depth of every tweet. Deleted or hidden tweets that have replies are kept as placeholders. Authentication is optional
+// @Tags tweet
+// @Produce json
+// @Param tweet_id path string true "Any tweet of the conversation"
+// @Param mode query string false "tree (default) or flat"
+// @Success 200 {object} models.GetConversationResponse
+// @Failure 400 {object} models.ResponseError "Invalid input"
+// @Failure 401 {object} models.ResponseError "Invalid token"
+// @Failure 404 {object} models.ResponseError "Tweet not found"
+// @Failure 500 {object} models.ResponseError "Internal server error"
+func (h *Controller) GetConversation(c *gin.Context) {
+	tweetID, err := uuid.Parse(c.Param("tweet_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ResponseError{
+			ErrorMessage: "Invalid UUID format: " + err.Error(),
+			ErrorCode:    "Bad Request",
+		})
+		return
+	}
+
+	mode := c.DefaultQuery("mode", "tree")
+	if mode != "tree" && mode != "flat" {
+		c.JSON(http.StatusBadRequest, models.ResponseError{
+			ErrorMessage: "mode must be tree or flat",
+			ErrorCode:    "Bad Request",
+		})
+		return
+	}
+
+	viewer := viewerID(c)
+	tweet, ok := h.visibleTweet(c, viewer, tweetID)
+	if !ok {
+		return
+	}
+
+	conversationID := tweet.ConversationID
+	if conversationID == uuid.Nil {
+		conversationID = tweet.Id
+	}
+
+	tweets, err := h.store.Tweet().GetConversation(conversationID, maxConversationSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ResponseError{
+			ErrorMessage: "Error while retrieving the conversation: " + err.Error(),
+			ErrorCode:    "Internal Server Error",
+		})
+		return
+	}
+
+	roots, err := h.conversationTree(viewer, tweets)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ResponseError{
+			ErrorMessage: "Error while retrieving the conversation: " + err.Error(),
+			ErrorCode:    "Internal Server Error",
+		})
+		return
+	}
+
+	resp := models.GetConversationResponse{ConversationID: conversationID, Tweets: roots}
+	if mode == "flat" {
+		resp.Tweets = flattenConversation(roots, nil)
+		resp.Count = len(resp.Tweets)
+	} else {
+		resp.Count = countConversation(roots)
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// @Router /v1/tweets/replies/{tweet_id} [get]
+// @Summary Get replies to a tweet
+// @Description API for retrieving the direct replies to a tweet, oldest first. Authentication is optional
+// @Tags tweet
+// @Produce json
+// @Param tweet_id path string true "Tweet ID"
+// @Param page query int false "Page number"
+// @Param limit query int false "Number of replies per page"
+// @Success 200 {object} models.GetTweetViewsResponse
+// @Failure 400 {object} models.ResponseError "Invalid input"
+// @Failure 401 {object} models.ResponseError "Invalid token"
+// @Failure 500 {object} models.ResponseError "Internal server error"
+func (h *Controller) GetReplies(c *gin.Context) {
+	tweetID, err := uuid.Parse(c.Param("tweet_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ResponseError{
+			ErrorMessage: "Invalid UUID format: " + err.Error(),
+			ErrorCode:    "Bad Request",
+		})
+		return
+	}
+
+	page, err := ParsePageQueryParam(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ResponseError{
+			ErrorMessage: "Invalid page: " + err.Error(),
+			ErrorCode:    "Bad Request",
+		})
+		return
+	}
+
+	limit, err := ParseLimitQueryParam(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ResponseError{
+			ErrorMessage: "Invalid limit: " + err.Error(),
+			ErrorCode:    "Bad Request",
+		})
+		return
+	}
+
+	req := models.GetAllTweetsRequest{
+		Page:        page,
+		Limit:       limit,
+		InReplyToID: tweetID.String(),
+		ViewerID:    viewerID(c),
+	}
+
+	replies, err := h.store.Tweet().GetAll(req)
+	var resp *models.GetTweetViewsResponse
+	if err == nil {
+		resp, err = h.tweetViewsResponse(req.ViewerID, replies)
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ResponseError{
+			ErrorMessage: "Error while retrieving replies: " + err.Error(),
+			ErrorCode:    "Internal Server Error",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// visibleTweet loads a tweet the viewer may see and answers 404 otherwise.
+func (h *Controller) visibleTweet(c *gin.Context, viewer, tweetID uuid.UUID) (*models.Tweet, bool) {
+	tweet, err := h.store.Tweet().Get(models.RequestId{Id: tweetID})
+	if err == nil && viewer != uuid.Nil {
+		var blocked bool
+		blocked, err = h.store.Block().EitherBlocked(viewer, tweet.UserID)
+		if err == nil && blocked {
+			err = gorm.ErrRecordNotFound
+		}
+	}
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, models.ResponseError{
+				ErrorMessage: "Tweet not found",
+				ErrorCode:    "Not Found",
+			})
+			return nil, false
+		}
+		c.JSON(http.StatusInternalServerError, models.ResponseError{
+			ErrorMessage: "Error while retrieving the tweet: " + err.Error(),
+			ErrorCode:    "Internal Server Error",
+		})
+		return nil, false
+	}
+
+	return tweet, true
+}
+
+// conversationTree arranges the tweets of a conversation, oldest first, into
+// trees. A reply whose parent is gone for good becomes a root of its own.
+// Tweets the viewer may not see are replaced by placeholders, which are
+// dropped unless they have replies.
+func (h *Controller) conversationTree(viewer uuid.UUID, tweets []models.Tweet) ([]*models.ConversationTweet, error) {
+	ids := make([]uuid.UUID, len(tweets))
+	for i := range tweets {
+		ids[i] = tweets[i].Id
+	}
+
+	visibleIDs, err := h.store.Tweet().VisibleIDs(viewer, ids)
+	if err != nil {
+		return nil, err
+	}
+	visible := make(map[uuid.UUID]bool, len(visibleIDs))
+	for _, id := range visibleIDs {
+		visible[id] = true
+	}
+
+	views, err := h.tweetViews(viewer, tweets)
+	if err != nil {
+		return nil, err
+	}
+
+	var roots []*models.ConversationTweet
+	nodes := make(map[uuid.UUID]*models.ConversationTweet, len(tweets))
+	for i, tweet := range tweets {
+		node := &models.ConversationTweet{TweetView: views[i]}
+		if !visible[tweet.Id] {
+			node.Placeholder = placeholderUnavailable
+			if tweet.DeletedAt.Valid {
+				node.Placeholder = placeholderDeleted
+			}
+			node.TweetView = models.TweetView{
+				Id:             tweet.Id,
+				Kind:           tweet.Kind,
+				InReplyToID:    tweet.InReplyToID,
+				ConversationID: tweet.ConversationID,
+				CreatedAt:      tweet.CreatedAt,
+			}
+		}
+		nodes[tweet.Id] = node
+
+		var parent *models.ConversationTweet
+		if tweet.InReplyToID != nil {
+			parent = nodes[*tweet.InReplyToID]
+		}
+		if parent == nil {
+			roots = append(roots, node)
+			continue
+		}
+		node.Depth = parent.Depth + 1
+		parent.Replies = append(parent.Replies, node)
+	}
+
+	return prunePlaceholders(roots), nil
+}
+
+func prunePlaceholders(nodes []*models.ConversationTweet) []*models.ConversationTweet {
+	kept := nodes[:0]
+	for _, node := range nodes {
+		node.Replies = prunePlaceholders(node.Replies)
+		if node.Placeholder == "" || len(node.Replies) > 0 {
+			kept = append(kept, node)
+		}
+	}
+	if len(kept) == 0 {
+		return nil
+	}
+	return kept
+}
+
+// flattenConversation lists the trees depth first, so every tweet is
+// followed by its replies.
+func flattenConversation(nodes []*models.ConversationTweet, list []*models.ConversationTweet) []*models.ConversationTweet {
+	for _, node := range nodes {
+		replies := node.Replies
+		node.Replies = nil
+		list = append(list, node)
+		list = flattenConversation(replies, list)
+	}
+	return list
+}
+
+func countConversation(nodes []*models.ConversationTweet) int {
+	count := len(nodes)
+	for _, node := range nodes {
+		count += countConversation(node.Replies)
+	}
+	return count
+}
@@ -0,0 +1,258 @@
+package controllers
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"net/http"
+	"project/database/storage"
+	"project/models"
+)
+
+// @Security ApiKeyAuth
+// @Router /v1/admin/roles [get]
+// @Summary Get all roles
+// @Description API for listing roles together with their permissions
+// @Tags admin
+// @Produce json
+// @Success 200 {object} models.GetAllRolesResponse
+// @Failure 403 {object} models.ResponseError "Forbidden"
+// @Failure 500 {object} models.ResponseError "Internal server error"
+func (h *Controller) GetAllRoles(c *gin.Context) {
+	roles, err := h.store.Role().GetAll()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ResponseError{
+			ErrorMessage: "Error while retrieving roles: " + err.Error(),
+			ErrorCode:    "Internal Server Error",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.GetAllRolesResponse{Roles: roles})
+}
+
+// @Security ApiKeyAuth
+// @Router /v1/admin/roles [post]
+// @Summary Create a role
+// @Description API for creating a custom role from existing permissions
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param role body models.CreateRole true "Role data"
+// @Success 200 {object} models.ResponseId
+// @Failure 400 {object} models.ResponseError "Invalid input"
+// @Failure 403 {object} models.ResponseError "Forbidden"
+// @Failure 500 {object} models.ResponseError "Internal server error"
+func (h *Controller) CreateRole(c *gin.Context) {
+	var roleModel models.CreateRole
+	if err := c.ShouldBindJSON(&roleModel); err != nil {
+		c.JSON(http.StatusBadRequest, models.ResponseError{
+			ErrorMessage: "Error while binding JSON: " + err.Error(),
+			ErrorCode:    "Bad Request",
+		})
+		return
+	}
+
+	permissions := make([]string, 0, len(roleModel.Permissions))
+	seen := make(map[string]bool, len(roleModel.Permissions))
+	for _, permission := range roleModel.Permissions {
+		if !seen[permission] {
+			seen[permission] = true
+			permissions = append(permissions, permission)
+		}
+	}
+
+	if _, err := h.store.Role().GetByName(roleModel.Name); err == nil {
+		c.JSON(http.StatusBadRequest, models.ResponseError{
+			ErrorMessage: "Role already exists",
+			ErrorCode:    "Bad Request",
+		})
+		return
+	}
+
+	role := models.Role{
+		Name:        roleModel.Name,
+		Description: roleModel.Description,
+	}
+
+	id, err := h.store.Role().Create(&role, permissions)
+	if err != nil {
+		if errors.Is(err, storage.ErrUnknownPermission) {
+			c.JSON(http.StatusBadRequest, models.ResponseError{
+				ErrorMessage: "Unknown permission in list",
+				ErrorCode:    "Bad Request",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, models.ResponseError{
+			ErrorMessage: "Error while creating a role: " + err.Error(),
+			ErrorCode:    "Internal Server Error",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ResponseId{Id: id})
+}
+
+// @Security ApiKeyAuth
+// @Router /v1/admin/users/{user_id}/roles [get]
+// @Summary Get roles of a user
+// @Description API for listing the roles granted to a user
+// @Tags admin
+// @Produce json
+// @Param user_id path string true "User ID"
+// @Success 200 {object} models.GetAllRolesResponse
+// @Failure 400 {object} models.ResponseError "Invalid input"
+// @Failure 403 {object} models.ResponseError "Forbidden"
+// @Failure 500 {object} models.ResponseError "Internal server error"
+func (h *Controller) GetUserRoles(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("user_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ResponseError{
+			ErrorMessage: "Invalid UUID format: " + err.Error(),
+			ErrorCode:    "Bad Request",
+		})
+		return
+	}
+
+	roles, err := h.store.Role().GetForUser(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ResponseError{
+			ErrorMessage: "Error while retrieving roles: " + err.Error(),
+			ErrorCode:    "Internal Server Error",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.GetAllRolesResponse{Roles: roles})
+}
+
+// @Security ApiKeyAuth
+// @Router /v1/admin/users/{user_id}/roles [post]
+// @Summary Grant a role
+// @Description API for granting a role to a user. Takes effect on the user's next request
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param user_id path string true "User ID"
+// @Param role body models.AssignRole true "Role name"
+// @Success 200 {object} models.ResponseSuccess
+// @Failure 400 {object} models.ResponseError "Invalid input"
+// @Failure 403 {object} models.ResponseError "Forbidden"
+// @Failure 404 {object} models.ResponseError "Not found"
+// @Failure 500 {object} models.ResponseError "Internal server error"
+func (h *Controller) AssignRole(c *gin.Context) {
+	var req models.AssignRole
+	userID, err := uuid.Parse(c.Param("user_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ResponseError{
+			ErrorMessage: "Invalid UUID format: " + err.Error(),
+			ErrorCode:    "Bad Request",
+		})
+		return
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ResponseError{
+			ErrorMessage: "Error while binding JSON: " + err.Error(),
+			ErrorCode:    "Bad Request",
+		})
+		return
+	}
+
+	role, ok := h.lookupUserAndRole(c, userID, req.Role)
+	if !ok {
+		return
+	}
+
+	if err := h.store.Role().Assign(userID, role.Id); err != nil {
+		c.JSON(http.StatusInternalServerError, models.ResponseError{
+			ErrorMessage: "Error while granting the role: " + err.Error(),
+			ErrorCode:    "Internal Server Error",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ResponseSuccess{
+		Message: "Role granted successfully",
+	})
+}
+
+// @Security ApiKeyAuth
+// @Router /v1/admin/users/{user_id}/roles/{role} [delete]
+// @Summary Revoke a role
+// @Description API for revoking a role from a user. Takes effect on the user's next request
+// @Tags admin
+// @Produce json
+// @Param user_id path string true "User ID"
+// @Param role path string true "Role name"
+// @Success 200 {object} models.ResponseSuccess
+// @Failure 400 {object} models.ResponseError "Invalid input"
+// @Failure 403 {object} models.ResponseError "Forbidden"
+// @Failure 404 {object} models.ResponseError "Not found"
+// @Failure 500 {object} models.ResponseError "Internal server error"
+func (h *Controller) RevokeRole(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("user_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ResponseError{
+			ErrorMessage: "Invalid UUID format: " + err.Error(),
+			ErrorCode:    "Bad Request",
+		})
+		return
+	}
+
+	if userID.String() == c.GetString("userID") {
+		c.JSON(http.StatusBadRequest, models.ResponseError{
+			ErrorMessage: "You cannot revoke your own roles",
+			ErrorCode:    "Bad Request",
+		})
+		return
+	}
+
+	role, ok := h.lookupUserAndRole(c, userID, c.Param("role"))
+	if !ok {
+		return
+	}
+
+	if err := h.store.Role().Revoke(userID, role.Id); err != nil {
+		c.JSON(http.StatusInternalServerError, models.ResponseError{
+			ErrorMessage: "Error while revoking the role: " + err.Error(),
+			ErrorCode:    "Internal Server Error",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ResponseSuccess{
+		Message: "Role revoked successfully",
+	})
+}
+
+func (h *Controller) lookupUserAndRole(c *gin.Context, userID uuid.UUID, roleName string) (*models.Role, bool) {
+	if _, err := h.store.User().Get(models.RequestId{Id: userID}); err != nil {
+		respondLookupError(c, err, "User not found", "Error while retrieving the user: ")
+		return nil, false
+	}
+
+	role, err := h.store.Role().GetByName(roleName)
+	if err != nil {
+		respondLookupError(c, err, "Role not found", "Error while retrieving the role: ")
+		return nil, false
+	}
+
+	return role, true
+}
+
+func respondLookupError(c *gin.Context, err error, notFound, internal string) {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, models.ResponseError{
+			ErrorMessage: notFound,
+			ErrorCode:    "Not Found",
+		})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, models.ResponseError{
+		ErrorMessage: internal + err.Error(),
+		ErrorCode:    "Internal Server Error",
+	})
+}
@@ -0,0 +1,200 @@
+package controllers
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"net/http"
+	"project/models"
+	"time"
+)
+
+// scheduleTweet stores a tweet to be published later and answers with the
+// id of the scheduled tweet.
+func (h *Controller) scheduleTweet(c *gin.Context, tweet models.ScheduledTweet) {
+	if !tweet.PublishAt.After(time.Now()) {
+		c.JSON(http.StatusBadRequest, models.ResponseError{
+			ErrorMessage: "publish_at must be in the future",
+			ErrorCode:    "Bad Request",
+		})
+		return
+	}
+
+	id, err := h.store.ScheduledTweet().Create(&tweet)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ResponseError{
+			ErrorMessage: "Error while scheduling the tweet: " + err.Error(),
+			ErrorCode:    "Internal Server Error",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ResponseId{Id: id})
+}
+
+// @Security ApiKeyAuth
+// @Router /v1/tweets/scheduled [get]
+// @Summary Get scheduled tweets
+// @Description API for retrieving the current user's tweets that are waiting to be published, the next one first. Tweets that failed to publish carry failed_at and last_error
+// @Tags tweet
+// @Produce json
+// @Param page query int false "Page number"
+// @Param limit query int false "Number of tweets per page"
+// @Success 200 {object} models.GetScheduledTweetsResponse
+// @Failure 400 {object} models.ResponseError "Invalid input"
+// @Failure 401 {object} models.ResponseError "Unauthorized"
+// @Failure 500 {object} models.ResponseError "Internal server error"
+func (h *Controller) GetScheduledTweets(c *gin.Context) {
+	page, err := ParsePageQueryParam(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ResponseError{
+			ErrorMessage: "Invalid page: " + err.Error(),
+			ErrorCode:    "Bad Request",
+		})
+		return
+	}
+
+	limit, err := ParseLimitQueryParam(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ResponseError{
+			ErrorMessage: "Invalid limit: " + err.Error(),
+			ErrorCode:    "Bad Request",
+		})
+		return
+	}
+
+	userID, err := uuid.Parse(c.GetString("userID"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ResponseError{
+			ErrorMessage: "Invalid UUID format from token: " + err.Error(),
+			ErrorCode:    "Bad Request",
+		})
+		return
+	}
+
+	tweets, err := h.store.ScheduledTweet().GetAllForUser(models.GetScheduledTweetsRequest{
+		UserID: userID,
+		Page:   page,
+		Limit:  limit,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ResponseError{
+			ErrorMessage: "Error while retrieving scheduled tweets: " + err.Error(),
+			ErrorCode:    "Internal Server Error",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, tweets)
+}
+
+// @Security ApiKeyAuth
+// @Router /v1/tweets/scheduled/{scheduled_id} [put]
+// @Summary Reschedule a tweet
+// @Description API for moving a scheduled tweet that has not been published yet. A tweet that failed to publish is tried again at the new time
+// @Tags tweet
+// @Accept json
+// @Produce json
+// @Param scheduled_id path string true "Scheduled tweet ID"
+// @Param schedule body models.RescheduleTweet true "New publishing time"
+// @Success 200 {object} models.ResponseSuccess
+// @Failure 400 {object} models.ResponseError "Invalid input"
+// @Failure 401 {object} models.ResponseError "Unauthorized"
+// @Failure 404 {object} models.ResponseError "Scheduled tweet not found or already published"
+// @Failure 500 {object} models.ResponseError "Internal server error"
+func (h *Controller) RescheduleTweet(c *gin.Context) {
+	id, userID, ok := scheduledTweetParams(c)
+	if !ok {
+		return
+	}
+
+	var req models.RescheduleTweet
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ResponseError{
+			ErrorMessage: "Error while binding JSON: " + err.Error(),
+			ErrorCode:    "Bad Request",
+		})
+		return
+	}
+
+	if !req.PublishAt.After(time.Now()) {
+		c.JSON(http.StatusBadRequest, models.ResponseError{
+			ErrorMessage: "publish_at must be in the future",
+			ErrorCode:    "Bad Request",
+		})
+		return
+	}
+
+	if err := h.store.ScheduledTweet().Reschedule(id, userID, req.PublishAt); err != nil {
+		scheduledTweetError(c, err, "Error while rescheduling the tweet: ")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ResponseSuccess{
+		Message: "Tweet rescheduled successfully",
+	})
+}
+
+// @Security ApiKeyAuth
+// @Router /v1/tweets/scheduled/{scheduled_id} [delete]
+// @Summary Cancel a scheduled tweet
+// @Description API for deleting a scheduled tweet that has not been published yet
+// @Tags tweet
+// @Param scheduled_id path string true "Scheduled tweet ID"
+// @Success 200 {object} models.ResponseSuccess
+// @Failure 400 {object} models.ResponseError "Invalid input"
+// @Failure 401 {object} models.ResponseError "Unauthorized"
+// @Failure 404 {object} models.ResponseError "Scheduled tweet not found or already published"
+// @Failure 500 {object} models.ResponseError "Internal server error"
+func (h *Controller) CancelScheduledTweet(c *gin.Context) {
+	id, userID, ok := scheduledTweetParams(c)
+	if !ok {
+		return
+	}
+
+	if err := h.store.ScheduledTweet().Cancel(id, userID); err != nil {
+		scheduledTweetError(c, err, "Error while cancelling the tweet: ")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ResponseSuccess{
+		Message: "Scheduled tweet cancelled successfully",
+	})
+}
+
+func scheduledTweetParams(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("scheduled_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ResponseError{
+			ErrorMessage: "Invalid UUID format: " + err.Error(),
+			ErrorCode:    "Bad Request",
+		})
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	userID, err := uuid.Parse(c.GetString("userID"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ResponseError{
+			ErrorMessage: "Invalid UUID format from token: " + err.Error(),
+			ErrorCode:    "Bad Request",
+		})
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	return id, userID, true
+}
+
+func scheduledTweetError(c *gin.Context, err error, message string) {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, models.ResponseError{
+			ErrorMessage: "Scheduled tweet not found or already published",
+			ErrorCode:    "Not Found",
+		})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, models.ResponseError{
+		ErrorMessage: message + err.Error(),
+		ErrorCode:    "Internal Server Error",
+	})
+}
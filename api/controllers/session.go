@@ -0,0 +1,189 @@
+package controllers
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"net/http"
+	auth "project/etc/jwt"
+	"project/models"
+	"time"
+)
+
+// maxUserAgentLength matches the size of the user agent columns.
+const maxUserAgentLength = 512
+
+// @Security ApiKeyAuth
+// @Router /v1/sessions [get]
+// @Summary Get active sessions
+// @Description API for listing the devices the current user is logged in on
+// @Tags auth
+// @Produce json
+// @Success 200 {object} models.GetAllSessionsResponse
+// @Failure 400 {object} models.ResponseError "Invalid input"
+// @Failure 401 {object} models.ResponseError "Unauthorized"
+// @Failure 500 {object} models.ResponseError "Internal server error"
+func (h *Controller) GetAllSessions(c *gin.Context) {
+	claims, ok := claimsFromContext(c)
+	if !ok {
+		return
+	}
+
+	userID, err := uuid.Parse(claims.UserID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ResponseError{
+			ErrorMessage: "Invalid UUID format from token: " + err.Error(),
+			ErrorCode:    "Bad Request",
+		})
+		return
+	}
+
+	// A session whose refresh token has expired cannot be resumed.
+	sessions, err := h.store.Session().GetAllForUser(userID, time.Now().Add(-h.cfg.RefreshTokenTTL))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ResponseError{
+			ErrorMessage: "Error while retrieving sessions: " + err.Error(),
+			ErrorCode:    "Internal Server Error",
+		})
+		return
+	}
+
+	for i := range sessions {
+		sessions[i].Current = sessions[i].Id.String() == claims.SessionID
+	}
+
+	c.JSON(http.StatusOK, models.GetAllSessionsResponse{Sessions: sessions})
+}
+
+// @Security ApiKeyAuth
+// @Router /v1/sessions/{session_id} [delete]
+// @Summary Revoke a session
+// @Description API for logging one of the current user's devices out
+// @Tags auth
+// @Produce json
+// @Param session_id path string true "Session ID"
+// @Success 200 {object} models.ResponseSuccess
+// @Failure 400 {object} models.ResponseError "Invalid input"
+// @Failure 401 {object} models.ResponseError "Unauthorized"
+// @Failure 404 {object} models.ResponseError "Session not found"
+// @Failure 500 {object} models.ResponseError "Internal server error"
+func (h *Controller) RevokeSession(c *gin.Context) {
+	sessionID, err := uuid.Parse(c.Param("session_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ResponseError{
+			ErrorMessage: "Invalid UUID format: " + err.Error(),
+			ErrorCode:    "Bad Request",
+		})
+		return
+	}
+
+	userID, err := uuid.Parse(c.GetString("userID"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ResponseError{
+			ErrorMessage: "Invalid UUID format from token: " + err.Error(),
+			ErrorCode:    "Bad Request",
+		})
+		return
+	}
+
+	if err := h.store.Session().Revoke(sessionID, userID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, models.ResponseError{
+				ErrorMessage: "Session not found",
+				ErrorCode:    "Not Found",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, models.ResponseError{
+			ErrorMessage: "Error while revoking the session: " + err.Error(),
+			ErrorCode:    "Internal Server Error",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ResponseSuccess{
+		Message: "Session revoked successfully",
+	})
+}
+
+// @Security ApiKeyAuth
+// @Router /v1/sessions/history [get]
+// @Summary Get login history
+// @Description API for listing the successful logins of the current user, newest first. Logins from a device that was not used before are flagged
+// @Tags auth
+// @Produce json
+// @Param page query uint64 false "page"
+// @Param limit query uint64 false "limit"
+// @Success 200 {object} models.GetLoginHistoryResponse
+// @Failure 400 {object} models.ResponseError "Invalid input"
+// @Failure 401 {object} models.ResponseError "Unauthorized"
+// @Failure 500 {object} models.ResponseError "Internal server error"
+func (h *Controller) GetLoginHistory(c *gin.Context) {
+	page, err := ParsePageQueryParam(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ResponseError{
+			ErrorMessage: "Error while parsing page: " + err.Error(),
+			ErrorCode:    "Bad Request",
+		})
+		return
+	}
+
+	limit, err := ParseLimitQueryParam(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ResponseError{
+			ErrorMessage: "Error while parsing limit: " + err.Error(),
+			ErrorCode:    "Bad Request",
+		})
+		return
+	}
+
+	userID, err := uuid.Parse(c.GetString("userID"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ResponseError{
+			ErrorMessage: "Invalid UUID format from token: " + err.Error(),
+			ErrorCode:    "Bad Request",
+		})
+		return
+	}
+
+	resp, err := h.store.Session().GetLoginHistory(models.GetLoginHistoryRequest{
+		Page:   page,
+		Limit:  limit,
+		UserID: userID,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ResponseError{
+			ErrorMessage: "Error while retrieving login history: " + err.Error(),
+			ErrorCode:    "Internal Server Error",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// recordSession stores the session of a login together with an entry in
+// the login history, both describing the device the request came from.
+func (h *Controller) recordSession(c *gin.Context, userID, sessionID uuid.UUID) error {
+	userAgent := c.Request.UserAgent()
+	if len(userAgent) > maxUserAgentLength {
+		userAgent = userAgent[:maxUserAgentLength]
+	}
+
+	now := time.Now()
+	session := models.Session{
+		Id:         sessionID,
+		UserID:     userID,
+		UserAgent:  userAgent,
+		IP:         c.ClientIP(),
+		LastSeenAt: now,
+		CreatedAt:  now,
+	}
+	event := models.LoginEvent{
+		DeviceHash: auth.HashToken(userAgent),
+		UserAgent:  userAgent,
+		IP:         session.IP,
+	}
+	return h.store.Session().Create(&session, &event)
+}
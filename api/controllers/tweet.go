@@ -1,16 +1,23 @@
 package controllers
 
 import (
+	"errors"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 	"net/http"
+	"project/database/storage"
+	"project/etc/tweettext"
 	"project/models"
+	"strconv"
+	"strings"
+	"time"
 )
 
 // @Security ApiKeyAuth
 // @Router /v1/tweets [post]
 // @Summary Create a tweet
-// @Description API for creating a new tweet
+// @Description API for creating a new tweet. With publish_at the tweet is scheduled instead and the id of the scheduled tweet is returned
 // @Tags tweet
 // @Accept json
 // @Produce json
@@ -48,20 +55,36 @@ func (h *Controller) CreateTweet(c *gin.Context) {
 		return
 	}
 
-	tweet := models.Tweet{
+	if tweetModel.PublishAt != nil {
+		// Entities are extracted when the tweet is published, so mentions
+		// name the accounts that exist by then.
+		content, ok := h.validContent(c, tweetModel.Content)
+		if !ok {
+			return
+		}
+		if !h.validPoll(c, tweetModel.Poll, tweetModel.ImagePath != nil || tweetModel.VideoPath != nil) {
+			return
+		}
+
+		h.scheduleTweet(c, models.ScheduledTweet{
+			UserID:    userId,
+			Content:   content,
+			ImagePath: tweetModel.ImagePath,
+			VideoPath: tweetModel.VideoPath,
+			Poll:      tweetModel.Poll,
+			PublishAt: *tweetModel.PublishAt,
+		})
+		return
+	}
+
+	id, ok := h.postTweet(c, &models.Tweet{
 		UserID:    userId,
 		Content:   tweetModel.Content,
-		RetweetID: tweetModel.RetweetID,
 		VideoPath: tweetModel.VideoPath,
 		ImagePath: tweetModel.ImagePath,
-	}
-
-	id, err := h.store.Tweet().Create(&tweet)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.ResponseError{
-			ErrorMessage: "Error while creating a tweet: " + err.Error(),
-			ErrorCode:    "Internal Server Error",
-		})
+		Poll:      tweetModel.Poll,
+	})
+	if !ok {
 		return
 	}
 
@@ -71,7 +94,7 @@ func (h *Controller) CreateTweet(c *gin.Context) {
 // @Security ApiKeyAuth
 // @Router /v1/tweets/{tweet_id} [put]
 // @Summary Update a tweet
-// @Description API for updating a tweet
+// @Description API for editing a tweet. The replaced version is kept in the tweet's history. Tweets can only be edited a limited number of times shortly after posting
 // @Tags tweet
 // @Accept json
 // @Produce json
@@ -79,37 +102,103 @@ func (h *Controller) CreateTweet(c *gin.Context) {
 // @Param tweet body models.CreateUpdateTweet true "Tweet data"
 // @Success 200 {object} models.ResponseSuccess
 // @Failure 400 {object} models.ResponseError "Invalid input"
+// @Failure 403 {object} models.ResponseError "Not the owner of the tweet, or the edit window or limit is exceeded"
+// @Failure 404 {object} models.ResponseError "Tweet not found"
+// @Failure 409 {object} models.ResponseError "Tweet was edited concurrently"
 // @Failure 500 {object} models.ResponseError "Internal server error"
 func (h *Controller) UpdateTweet(c *gin.Context) {
 	var tweetModel models.CreateUpdateTweet
-	id := c.Param("tweet_id")
+	if err := c.ShouldBindJSON(&tweetModel); err != nil {
+		c.JSON(http.StatusBadRequest, models.ResponseError{
+			ErrorMessage: "Error while binding JSON: " + err.Error(),
+			ErrorCode:    "Bad Request",
+		})
+		return
+	}
 
-	parsedID, err := uuid.Parse(id)
-	if err != nil {
+	if tweetModel.PublishAt != nil {
 		c.JSON(http.StatusBadRequest, models.ResponseError{
-			ErrorMessage: "Invalid UUID format: " + err.Error(),
+			ErrorMessage: "Only new tweets can be scheduled",
 			ErrorCode:    "Bad Request",
 		})
 		return
 	}
 
-	if err := c.ShouldBindJSON(&tweetModel); err != nil {
+	if tweetModel.Poll != nil {
 		c.JSON(http.StatusBadRequest, models.ResponseError{
-			ErrorMessage: "Error while binding JSON: " + err.Error(),
+			ErrorMessage: "A poll cannot be added to or changed in a posted tweet",
 			ErrorCode:    "Bad Request",
 		})
 		return
 	}
 
-	tweet := models.Tweet{
-		Id:        parsedID,
-		Content:   tweetModel.Content,
-		RetweetID: tweetModel.RetweetID,
-		VideoPath: tweetModel.VideoPath,
-		ImagePath: tweetModel.ImagePath,
+	tweet := c.MustGet("tweet").(*models.Tweet)
+	if tweet.Kind == models.TweetKindRetweet {
+		c.JSON(http.StatusBadRequest, models.ResponseError{
+			ErrorMessage: "Retweets cannot be edited",
+			ErrorCode:    "Bad Request",
+		})
+		return
+	}
+	if time.Since(tweet.CreatedAt) > h.cfg.TweetEditWindow {
+		c.JSON(http.StatusForbidden, models.ResponseError{
+			ErrorMessage: "Tweets can only be edited within " + h.cfg.TweetEditWindow.String() + " of posting",
+			ErrorCode:    "Forbidden",
+		})
+		return
+	}
+	if tweet.EditCount >= h.cfg.TweetEditLimit {
+		c.JSON(http.StatusForbidden, models.ResponseError{
+			ErrorMessage: "Tweets cannot be edited more than " + strconv.Itoa(h.cfg.TweetEditLimit) + " times",
+			ErrorCode:    "Forbidden",
+		})
+		return
 	}
 
-	if err := h.store.Tweet().Update(&tweet); err != nil {
+	if tweet.Kind == models.TweetKindQuote && strings.TrimSpace(tweetModel.Content) == "" {
+		c.JSON(http.StatusBadRequest, models.ResponseError{
+			ErrorMessage: "A quote needs commentary; use a retweet to share a tweet as is",
+			ErrorCode:    "Bad Request",
+		})
+		return
+	}
+
+	if tweetModel.ImagePath != nil || tweetModel.VideoPath != nil {
+		polls, err := h.store.Poll().GetByTweetIDs([]uuid.UUID{tweet.Id})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, models.ResponseError{
+				ErrorMessage: "Error while retrieving the poll: " + err.Error(),
+				ErrorCode:    "Internal Server Error",
+			})
+			return
+		}
+		if len(polls) > 0 {
+			c.JSON(http.StatusBadRequest, models.ResponseError{
+				ErrorMessage: "A tweet cannot have both media and a poll",
+				ErrorCode:    "Bad Request",
+			})
+			return
+		}
+	}
+
+	content, entities, ok := h.tweetContent(c, tweetModel.Content)
+	if !ok {
+		return
+	}
+
+	tweet.Content = content
+	tweet.Entities = entities
+	tweet.VideoPath = tweetModel.VideoPath
+	tweet.ImagePath = tweetModel.ImagePath
+
+	if err := h.store.Tweet().Update(tweet); err != nil {
+		if errors.Is(err, storage.ErrTweetEdited) {
+			c.JSON(http.StatusConflict, models.ResponseError{
+				ErrorMessage: "Tweet was edited in the meantime, reload it and try again",
+				ErrorCode:    "Conflict",
+			})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, models.ResponseError{
 			ErrorMessage: "Error while updating the tweet: " + err.Error(),
 			ErrorCode:    "Internal Server Error",
@@ -130,6 +219,8 @@ func (h *Controller) UpdateTweet(c *gin.Context) {
 // @Param tweet_id path string true "Tweet ID"
 // @Success 200 {object} models.ResponseSuccess
 // @Failure 400 {object} models.ResponseError "Invalid input"
+// @Failure 403 {object} models.ResponseError "Not the owner of the tweet"
+// @Failure 404 {object} models.ResponseError "Tweet not found"
 // @Failure 500 {object} models.ResponseError "Internal server error"
 func (h *Controller) DeleteTweet(c *gin.Context) {
 	idStr := c.Param("tweet_id")
@@ -152,11 +243,13 @@ func (h *Controller) DeleteTweet(c *gin.Context) {
 // @Security ApiKeyAuth
 // @Router /v1/tweets/{tweet_id} [get]
 // @Summary Get a tweet by ID
-// @Description API for retrieving a tweet by ID
+// @Description API for retrieving a tweet by ID. Authentication is optional and adds viewer-specific fields
 // @Tags tweet
 // @Param tweet_id path string true "Tweet ID"
-// @Success 200 {object} models.Tweet
+// @Success 200 {object} models.TweetView
 // @Failure 400 {object} models.ResponseError "Invalid input"
+// @Failure 401 {object} models.ResponseError "Invalid token"
+// @Failure 404 {object} models.ResponseError "Tweet not found"
 // @Failure 500 {object} models.ResponseError "Internal server error"
 func (h *Controller) GetTweet(c *gin.Context) {
 	idStr := c.Param("tweet_id")
@@ -171,6 +264,13 @@ func (h *Controller) GetTweet(c *gin.Context) {
 
 	tweet, err := h.store.Tweet().Get(models.RequestId{Id: id})
 	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, models.ResponseError{
+				ErrorMessage: "Tweet not found",
+				ErrorCode:    "Not Found",
+			})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, models.ResponseError{
 			ErrorMessage: "Error while retrieving the tweet: " + err.Error(),
 			ErrorCode:    "Internal Server Error",
@@ -178,20 +278,103 @@ func (h *Controller) GetTweet(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, tweet)
+	viewer := viewerID(c)
+	if viewer != uuid.Nil {
+		blocked, err := h.store.Block().EitherBlocked(viewer, tweet.UserID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, models.ResponseError{
+				ErrorMessage: "Error while checking blocks: " + err.Error(),
+				ErrorCode:    "Internal Server Error",
+			})
+			return
+		}
+		if blocked {
+			c.JSON(http.StatusNotFound, models.ResponseError{
+				ErrorMessage: "Tweet not found",
+				ErrorCode:    "Not Found",
+			})
+			return
+		}
+	}
+
+	views, err := h.tweetViews(viewer, []models.Tweet{*tweet})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ResponseError{
+			ErrorMessage: "Error while retrieving the tweet: " + err.Error(),
+			ErrorCode:    "Internal Server Error",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, views[0])
+}
+
+// @Router /v1/tweets/{tweet_id}/history [get]
+// @Summary Get the edit history of a tweet
+// @Description API for retrieving every version of a tweet, oldest first. Authentication is optional
+// @Tags tweet
+// @Produce json
+// @Param tweet_id path string true "Tweet ID"
+// @Success 200 {object} models.GetTweetHistoryResponse
+// @Failure 400 {object} models.ResponseError "Invalid input"
+// @Failure 401 {object} models.ResponseError "Invalid token"
+// @Failure 404 {object} models.ResponseError "Tweet not found"
+// @Failure 500 {object} models.ResponseError "Internal server error"
+func (h *Controller) GetTweetHistory(c *gin.Context) {
+	tweetID, err := uuid.Parse(c.Param("tweet_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ResponseError{
+			ErrorMessage: "Invalid UUID format: " + err.Error(),
+			ErrorCode:    "Bad Request",
+		})
+		return
+	}
+
+	tweet, ok := h.visibleTweet(c, viewerID(c), tweetID)
+	if !ok {
+		return
+	}
+
+	revisions, err := h.store.Tweet().GetRevisions(tweet.Id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ResponseError{
+			ErrorMessage: "Error while retrieving the tweet history: " + err.Error(),
+			ErrorCode:    "Internal Server Error",
+		})
+		return
+	}
+
+	postedAt := tweet.CreatedAt
+	if tweet.EditedAt != nil {
+		postedAt = *tweet.EditedAt
+	}
+	revisions = append(revisions, models.TweetRevision{
+		TweetID:   tweet.Id,
+		Version:   tweet.EditCount + 1,
+		Content:   tweet.Content,
+		ImagePath: tweet.ImagePath,
+		VideoPath: tweet.VideoPath,
+		CreatedAt: postedAt,
+	})
+
+	c.JSON(http.StatusOK, models.GetTweetHistoryResponse{
+		Revisions: revisions,
+		Count:     len(revisions),
+	})
 }
 
 // @Security ApiKeyAuth
 // @Router /v1/tweets [get]
 // @Summary Get all tweets
-// @Description API for retrieving all tweets with pagination and search
+// @Description API for retrieving all tweets with pagination and search. Authentication is optional; it hides users blocked either way and, unless user_id is given, muted users, and adds viewer-specific fields
 // @Tags tweet
 // @Param page query int false "Page number"
 // @Param limit query int false "Number of tweets per page"
 // @Param search query string false "Search term"
 // @Param user_id query string false "User ID for filtering tweets"
-// @Success 200 {object} models.GetAllTweetsResponse
+// @Success 200 {object} models.GetTweetViewsResponse
 // @Failure 400 {object} models.ResponseError "Invalid input"
+// @Failure 401 {object} models.ResponseError "Invalid token"
 // @Failure 500 {object} models.ResponseError "Internal server error"
 func (h *Controller) GetAllTweets(c *gin.Context) {
 	page, err := ParsePageQueryParam(c)
@@ -217,13 +400,18 @@ func (h *Controller) GetAllTweets(c *gin.Context) {
 	search := c.Query("search")
 
 	req := models.GetAllTweetsRequest{
-		Limit:  limit,
-		Page:   page,
-		UserID: userId,
-		Search: search,
+		Limit:    limit,
+		Page:     page,
+		UserID:   userId,
+		Search:   search,
+		ViewerID: viewerID(c),
 	}
 
 	tweets, err := h.store.Tweet().GetAll(req)
+	var resp *models.GetTweetViewsResponse
+	if err == nil {
+		resp, err = h.tweetViewsResponse(req.ViewerID, tweets)
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, models.ResponseError{
 			ErrorMessage: "Error while retrieving tweets: " + err.Error(),
@@ -232,7 +420,7 @@ func (h *Controller) GetAllTweets(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, tweets)
+	c.JSON(http.StatusOK, resp)
 }
 
 // @Security ApiKeyAuth
@@ -242,7 +430,7 @@ func (h *Controller) GetAllTweets(c *gin.Context) {
 // @Tags tweet
 // @Param page query int false "Page number"
 // @Param limit query int false "Number of tweets per page"
-// @Success 200 {object} models.GetAllTweetsResponse
+// @Success 200 {object} models.GetTweetViewsResponse
 // @Failure 400 {object} models.ResponseError "Invalid input"
 // @Failure 500 {object} models.ResponseError "Internal server error"
 func (h *Controller) GetTweetsFeed(c *gin.Context) {
@@ -288,6 +476,10 @@ func (h *Controller) GetTweetsFeed(c *gin.Context) {
 	}
 
 	tweets, err := h.store.Tweet().GetTweetsForUser(models.RequestId{Id: userID}, req)
+	var resp *models.GetTweetViewsResponse
+	if err == nil {
+		resp, err = h.tweetViewsResponse(userID, tweets)
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, models.ResponseError{
 			ErrorMessage: "Error while retrieving tweets feed: " + err.Error(),
@@ -296,17 +488,18 @@ func (h *Controller) GetTweetsFeed(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, tweets)
+	c.JSON(http.StatusOK, resp)
 }
 
 // @Security ApiKeyAuth
 // @Router /v1/tweets/retweet/{tweet_id} [post]
 // @Summary Retweets a tweet
-// @Description API for retweeting an existing tweet
+// @Description API for retweeting an existing tweet. Retweeting a retweet shares the original tweet, and retweeting a tweet again returns the existing retweet
 // @Tags tweet
 // @Param tweet_id path string true "Tweet ID to retweet"
 // @Success 200 {object} models.ResponseId
 // @Failure 400 {object} models.ResponseError "Invalid input"
+// @Failure 404 {object} models.ResponseError "Tweet not found"
 // @Failure 500 {object} models.ResponseError "Internal server error"
 func (h *Controller) Retweet(c *gin.Context) {
 	id := c.Param("tweet_id")
@@ -338,12 +531,12 @@ func (h *Controller) Retweet(c *gin.Context) {
 		return
 	}
 
-	newTweet := models.Tweet{
-		UserID:    userID,
-		RetweetID: &originalTweetID,
+	original, ok := h.sharedTweet(c, userID, originalTweetID)
+	if !ok {
+		return
 	}
 
-	retweetID, err := h.store.Tweet().Create(&newTweet)
+	retweetID, err := h.store.Tweet().Retweet(userID, original.Id)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, models.ResponseError{
 			ErrorMessage: "Error while creating a retweet: " + err.Error(),
@@ -354,3 +547,295 @@ func (h *Controller) Retweet(c *gin.Context) {
 
 	c.JSON(http.StatusOK, models.ResponseId{Id: retweetID})
 }
+
+// @Security ApiKeyAuth
+// @Router /v1/tweets/retweet/{tweet_id} [delete]
+// @Summary Undo a retweet
+// @Description API for removing the retweet of a tweet. Passing a retweet removes the retweet of the tweet it shares
+// @Tags tweet
+// @Param tweet_id path string true "Tweet ID that was retweeted"
+// @Success 200 {object} models.ResponseSuccess
+// @Failure 400 {object} models.ResponseError "Invalid input"
+// @Failure 500 {object} models.ResponseError "Internal server error"
+func (h *Controller) Unretweet(c *gin.Context) {
+	tweetID, err := uuid.Parse(c.Param("tweet_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ResponseError{
+			ErrorMessage: "Invalid UUID format: " + err.Error(),
+			ErrorCode:    "Bad Request",
+		})
+		return
+	}
+
+	userID, err := uuid.Parse(c.GetString("userID"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ResponseError{
+			ErrorMessage: "Invalid UUID format from token: " + err.Error(),
+			ErrorCode:    "Bad Request",
+		})
+		return
+	}
+
+	if err := h.store.Tweet().Unretweet(userID, tweetID); err != nil {
+		c.JSON(http.StatusInternalServerError, models.ResponseError{
+			ErrorMessage: "Error while removing the retweet: " + err.Error(),
+			ErrorCode:    "Internal Server Error",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ResponseSuccess{
+		Message: "Retweet removed successfully",
+	})
+}
+
+// @Router /v1/tweets/{tweet_id}/retweeters [get]
+// @Summary Get retweeters of a tweet
+// @Description API for retrieving the users who retweeted a tweet, latest first. Authentication is optional
+// @Tags tweet
+// @Produce json
+// @Param tweet_id path string true "Tweet ID"
+// @Param page query int false "Page number"
+// @Param limit query int false "Number of users per page"
+// @Success 200 {object} models.GetAllUsersResponse
+// @Failure 400 {object} models.ResponseError "Invalid input"
+// @Failure 401 {object} models.ResponseError "Invalid token"
+// @Failure 404 {object} models.ResponseError "Tweet not found"
+// @Failure 500 {object} models.ResponseError "Internal server error"
+func (h *Controller) GetRetweeters(c *gin.Context) {
+	tweetID, err := uuid.Parse(c.Param("tweet_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ResponseError{
+			ErrorMessage: "Invalid UUID format: " + err.Error(),
+			ErrorCode:    "Bad Request",
+		})
+		return
+	}
+
+	page, err := ParsePageQueryParam(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ResponseError{
+			ErrorMessage: "Invalid page: " + err.Error(),
+			ErrorCode:    "Bad Request",
+		})
+		return
+	}
+
+	limit, err := ParseLimitQueryParam(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ResponseError{
+			ErrorMessage: "Invalid limit: " + err.Error(),
+			ErrorCode:    "Bad Request",
+		})
+		return
+	}
+
+	tweet, ok := h.sharedTweet(c, viewerID(c), tweetID)
+	if !ok {
+		return
+	}
+
+	users, err := h.store.User().GetAll(models.GetAllUsersRequest{
+		Page:      page,
+		Limit:     limit,
+		Retweeted: tweet.Id,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ResponseError{
+			ErrorMessage: "Error while retrieving retweeters: " + err.Error(),
+			ErrorCode:    "Internal Server Error",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, users)
+}
+
+// @Security ApiKeyAuth
+// @Router /v1/tweets/quote/{tweet_id} [post]
+// @Summary Quote a tweet
+// @Description API for sharing a tweet with commentary. Quoting a retweet quotes the original tweet
+// @Tags tweet
+// @Accept json
+// @Produce json
+// @Param tweet_id path string true "Tweet ID to quote"
+// @Param tweet body models.CreateUpdateTweet true "Commentary"
+// @Success 200 {object} models.ResponseId
+// @Failure 400 {object} models.ResponseError "Invalid input"
+// @Failure 401 {object} models.ResponseError "Unauthorized"
+// @Failure 404 {object} models.ResponseError "Tweet not found"
+// @Failure 500 {object} models.ResponseError "Internal server error"
+func (h *Controller) QuoteTweet(c *gin.Context) {
+	quotedID, err := uuid.Parse(c.Param("tweet_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ResponseError{
+			ErrorMessage: "Invalid UUID format: " + err.Error(),
+			ErrorCode:    "Bad Request",
+		})
+		return
+	}
+
+	var tweetModel models.CreateUpdateTweet
+	if err := c.ShouldBindJSON(&tweetModel); err != nil {
+		c.JSON(http.StatusBadRequest, models.ResponseError{
+			ErrorMessage: "Error while binding JSON: " + err.Error(),
+			ErrorCode:    "Bad Request",
+		})
+		return
+	}
+
+	if tweetModel.PublishAt != nil {
+		c.JSON(http.StatusBadRequest, models.ResponseError{
+			ErrorMessage: "Only new tweets can be scheduled",
+			ErrorCode:    "Bad Request",
+		})
+		return
+	}
+
+	userID, err := uuid.Parse(c.GetString("userID"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ResponseError{
+			ErrorMessage: "Invalid UUID format from token: " + err.Error(),
+			ErrorCode:    "Bad Request",
+		})
+		return
+	}
+
+	id, ok := h.postTweet(c, &models.Tweet{
+		UserID:        userID,
+		Content:       tweetModel.Content,
+		ImagePath:     tweetModel.ImagePath,
+		VideoPath:     tweetModel.VideoPath,
+		QuotedTweetID: &quotedID,
+		Poll:          tweetModel.Poll,
+	})
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ResponseId{Id: id})
+}
+
+// postTweet validates and creates a new tweet, reply or quote, depending on
+// which of InReplyToID and QuotedTweetID is set, and answers with an error
+// when that fails.
+func (h *Controller) postTweet(c *gin.Context, tweet *models.Tweet) (string, bool) {
+	if !h.prepareTweet(c, tweet) {
+		return "", false
+	}
+
+	id, err := h.store.Tweet().Create(tweet)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ResponseError{
+			ErrorMessage: "Error while creating a tweet: " + err.Error(),
+			ErrorCode:    "Internal Server Error",
+		})
+		return "", false
+	}
+	return id, true
+}
+
+// prepareTweet validates a new tweet and answers with an error when it is
+// invalid. Content is normalized and its entities are extracted. Replying to
+// or quoting a retweet refers to the original tweet.
+func (h *Controller) prepareTweet(c *gin.Context, tweet *models.Tweet) bool {
+	if tweet.InReplyToID != nil && tweet.QuotedTweetID != nil {
+		c.JSON(http.StatusBadRequest, models.ResponseError{
+			ErrorMessage: "A tweet cannot both reply to and quote a tweet",
+			ErrorCode:    "Bad Request",
+		})
+		return false
+	}
+
+	if tweet.QuotedTweetID != nil && tweet.Poll != nil {
+		c.JSON(http.StatusBadRequest, models.ResponseError{
+			ErrorMessage: "A quote cannot have a poll",
+			ErrorCode:    "Bad Request",
+		})
+		return false
+	}
+
+	if !h.validPoll(c, tweet.Poll, tweet.ImagePath != nil || tweet.VideoPath != nil) {
+		return false
+	}
+
+	if tweet.QuotedTweetID != nil && strings.TrimSpace(tweet.Content) == "" {
+		c.JSON(http.StatusBadRequest, models.ResponseError{
+			ErrorMessage: "A quote needs commentary; use a retweet to share a tweet as is",
+			ErrorCode:    "Bad Request",
+		})
+		return false
+	}
+
+	var ok bool
+	tweet.Content, tweet.Entities, ok = h.tweetContent(c, tweet.Content)
+	if !ok {
+		return false
+	}
+
+	if tweet.InReplyToID != nil {
+		parent, ok := h.sharedTweet(c, tweet.UserID, *tweet.InReplyToID)
+		if !ok {
+			return false
+		}
+		tweet.InReplyToID = &parent.Id
+		tweet.ConversationID = parent.ConversationID
+		if tweet.ConversationID == uuid.Nil {
+			tweet.ConversationID = parent.Id
+		}
+	}
+
+	if tweet.QuotedTweetID != nil {
+		quoted, ok := h.sharedTweet(c, tweet.UserID, *tweet.QuotedTweetID)
+		if !ok {
+			return false
+		}
+		tweet.QuotedTweetID = &quoted.Id
+	}
+
+	return true
+}
+
+// validContent normalizes the content of a tweet and checks its length. It
+// answers 400 for invalid content.
+func (h *Controller) validContent(c *gin.Context, content string) (string, bool) {
+	content = tweettext.Normalize(content)
+	if err := tweettext.Validate(content, h.cfg.TweetMaxLength); err != nil {
+		c.JSON(http.StatusBadRequest, models.ResponseError{
+			ErrorMessage: "Invalid content: " + err.Error(),
+			ErrorCode:    "Bad Request",
+		})
+		return "", false
+	}
+	return content, true
+}
+
+// sharedTweet loads the tweet a retweet or quote of tweetID refers to, which
+// for a retweet is the tweet it shared.
+func (h *Controller) sharedTweet(c *gin.Context, viewer, tweetID uuid.UUID) (*models.Tweet, bool) {
+	tweet, ok := h.visibleTweet(c, viewer, tweetID)
+	if !ok || tweet.Kind != models.TweetKindRetweet || tweet.RetweetID == nil {
+		return tweet, ok
+	}
+	return h.visibleTweet(c, viewer, *tweet.RetweetID)
+}
+
+// tweetContent normalizes the content of a new or edited tweet, checks its
+// length and extracts its entities. Mentions of usernames without an active
+// account are left as plain text. It answers 400 for invalid content.
+func (h *Controller) tweetContent(c *gin.Context, content string) (string, *tweettext.Entities, bool) {
+	content, ok := h.validContent(c, content)
+	if !ok {
+		return "", nil, false
+	}
+
+	entities := tweettext.Extract(content)
+	if err := h.store.User().ResolveMentions(&entities); err != nil {
+		c.JSON(http.StatusInternalServerError, models.ResponseError{
+			ErrorMessage: "Error while resolving mentions: " + err.Error(),
+			ErrorCode:    "Internal Server Error",
+		})
+		return "", nil, false
+	}
+	return content, &entities, true
+}
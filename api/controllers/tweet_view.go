@@ -0,0 +1,182 @@
+package controllers
+
+import (
+	"github.com/google/uuid"
+	"project/models"
+	"time"
+)
+
+// tweetViews presents tweets to the viewer, which is uuid.Nil for anonymous
+// requests. The tweets they retweet or quote are embedded when the viewer
+// may see them. The number of queries does not depend on len(tweets).
+func (h *Controller) tweetViews(viewer uuid.UUID, tweets []models.Tweet) ([]models.TweetView, error) {
+	views := make([]models.TweetView, 0, len(tweets))
+	if len(tweets) == 0 {
+		return views, nil
+	}
+
+	var referencedIDs []uuid.UUID
+	for _, tweet := range tweets {
+		if tweet.RetweetID != nil {
+			referencedIDs = append(referencedIDs, *tweet.RetweetID)
+		}
+		if tweet.QuotedTweetID != nil {
+			referencedIDs = append(referencedIDs, *tweet.QuotedTweetID)
+		}
+	}
+
+	var referenced []models.Tweet
+	if len(referencedIDs) > 0 {
+		var err error
+		referenced, err = h.store.Tweet().GetVisible(viewer, referencedIDs)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	all := append(append(make([]models.Tweet, 0, len(tweets)+len(referenced)), tweets...), referenced...)
+	ids := make([]uuid.UUID, len(all))
+	userIDs := make([]uuid.UUID, len(all))
+	for i, tweet := range all {
+		ids[i] = tweet.Id
+		userIDs[i] = tweet.UserID
+		if tweet.Entities != nil {
+			for _, mention := range tweet.Entities.Mentions {
+				if id, err := uuid.Parse(mention.UserID); err == nil {
+					userIDs = append(userIDs, id)
+				}
+			}
+		}
+	}
+
+	// Authors and mentioned users are loaded together. Mentions of users
+	// that are gone turn back into plain text.
+	users, err := h.store.User().GetByIDs(userIDs)
+	if err != nil {
+		return nil, err
+	}
+	authors := make(map[uuid.UUID]*models.TweetAuthor, len(users))
+	for _, user := range users {
+		authors[user.Id] = &models.TweetAuthor{
+			Id:           user.Id,
+			Name:         user.Name,
+			Username:     user.Username,
+			ProfileImage: user.ProfileImage,
+		}
+	}
+
+	likes, err := h.store.Like().CountByTweet(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	polls, err := h.store.Poll().GetByTweetIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+	pollIDs := make([]uuid.UUID, len(polls))
+	tweetPolls := make(map[uuid.UUID]models.Poll, len(polls))
+	for i, poll := range polls {
+		pollIDs[i] = poll.Id
+		tweetPolls[poll.TweetID] = poll
+	}
+
+	var liked, retweeted map[uuid.UUID]bool
+	var voted map[uuid.UUID]uuid.UUID
+	if viewer != uuid.Nil {
+		likedIDs, err := h.store.Like().LikedTweetIDs(viewer, ids)
+		if err != nil {
+			return nil, err
+		}
+		retweetedIDs, err := h.store.Tweet().RetweetedIDs(viewer, ids)
+		if err != nil {
+			return nil, err
+		}
+		liked, retweeted = idSet(likedIDs), idSet(retweetedIDs)
+
+		if len(pollIDs) > 0 {
+			voted, err = h.store.Poll().VotedOptions(viewer, pollIDs)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	now := time.Now()
+
+	view := func(tweet models.Tweet) models.TweetView {
+		v := models.TweetView{
+			Id:             tweet.Id,
+			Kind:           tweet.Kind,
+			Content:        tweet.Content,
+			ImagePath:      tweet.ImagePath,
+			VideoPath:      tweet.VideoPath,
+			InReplyToID:    tweet.InReplyToID,
+			ConversationID: tweet.ConversationID,
+			RetweetID:      tweet.RetweetID,
+			QuotedTweetID:  tweet.QuotedTweetID,
+			Author:         authors[tweet.UserID],
+			LikeCount:      likes[tweet.Id],
+			ReplyCount:     tweet.ReplyCount,
+			RetweetCount:   tweet.RetweetCount,
+			QuoteCount:     tweet.QuoteCount,
+			EditCount:      tweet.EditCount,
+			EditedAt:       tweet.EditedAt,
+			CreatedAt:      tweet.CreatedAt,
+			UpdatedAt:      tweet.UpdatedAt,
+		}
+		if tweet.Entities != nil {
+			v.Entities = *tweet.Entities
+			v.Entities.Mentions = nil
+			for _, mention := range tweet.Entities.Mentions {
+				if id, err := uuid.Parse(mention.UserID); err == nil && authors[id] != nil {
+					v.Entities.Mentions = append(v.Entities.Mentions, mention)
+				}
+			}
+		}
+		if poll, ok := tweetPolls[tweet.Id]; ok {
+			v.Poll = pollView(poll, voted[poll.Id], now)
+		}
+		if viewer != uuid.Nil {
+			isLiked, isRetweeted := liked[tweet.Id], retweeted[tweet.Id]
+			v.LikedByMe = &isLiked
+			v.RetweetedByMe = &isRetweeted
+		}
+		return v
+	}
+
+	referencedViews := make(map[uuid.UUID]*models.TweetView, len(referenced))
+	for _, tweet := range referenced {
+		v := view(tweet)
+		referencedViews[tweet.Id] = &v
+	}
+
+	for _, tweet := range tweets {
+		v := view(tweet)
+		if tweet.RetweetID != nil {
+			v.Retweeted = referencedViews[*tweet.RetweetID]
+		}
+		if tweet.QuotedTweetID != nil {
+			v.Quoted = referencedViews[*tweet.QuotedTweetID]
+		}
+		views = append(views, v)
+	}
+	return views, nil
+}
+
+// tweetViewsResponse presents a page of tweets to the viewer.
+func (h *Controller) tweetViewsResponse(viewer uuid.UUID, page *models.GetAllTweetsResponse) (*models.GetTweetViewsResponse, error) {
+	views, err := h.tweetViews(viewer, page.Tweets)
+	if err != nil {
+		return nil, err
+	}
+	return &models.GetTweetViewsResponse{Tweets: views, Count: page.Count}, nil
+}
+
+func idSet(ids []uuid.UUID) map[uuid.UUID]bool {
+	set := make(map[uuid.UUID]bool, len(ids))
+	for _, id := range ids {
+		set[id] = true
+	}
+	return set
+}
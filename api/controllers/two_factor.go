@@ -0,0 +1,551 @@
+package controllers
+
+import (
+	"crypto/rand"
+	"encoding/base32"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"net/http"
+	auth "project/etc/jwt"
+	"project/etc/totp"
+	"project/models"
+	"strings"
+	"time"
+)
+
+const recoveryCodeCount = 10
+
+// finishPasswordLogin is called once the password of user has been checked.
+// Depending on the two-factor state of the account it either logs the user
+// in or hands out the token for the next step.
+func (h *Controller) finishPasswordLogin(c *gin.Context, user *models.User) {
+	if user.TOTPEnabledAt != nil {
+		token, err := h.keys.GenerateTypedToken(user.Id.String(), auth.TokenChallenge, h.cfg.TwoFactorTokenTTL)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, models.ResponseError{
+				ErrorMessage: "Error while generating token: " + err.Error(),
+				ErrorCode:    "Internal Server Error",
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, models.LoginResponse{
+			TwoFactorRequired: true,
+			ChallengeToken:    token,
+			ExpiresIn:         int64(h.cfg.TwoFactorTokenTTL.Seconds()),
+		})
+		return
+	}
+
+	required, err := h.twoFactorRequired(user)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ResponseError{
+			ErrorMessage: "Error while loading permissions: " + err.Error(),
+			ErrorCode:    "Internal Server Error",
+		})
+		return
+	}
+
+	if required {
+		token, err := h.keys.GenerateTypedToken(user.Id.String(), auth.TokenEnrollment, h.cfg.AccessTokenTTL)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, models.ResponseError{
+				ErrorMessage: "Error while generating token: " + err.Error(),
+				ErrorCode:    "Internal Server Error",
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, models.LoginResponse{
+			TwoFactorEnrollmentRequired: true,
+			EnrollmentToken:             token,
+			ExpiresIn:                   int64(h.cfg.AccessTokenTTL.Seconds()),
+		})
+		return
+	}
+
+	resp, err := h.createSession(c, user)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ResponseError{
+			ErrorMessage: "Error while generating tokens: " + err.Error(),
+			ErrorCode:    "Internal Server Error",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// twoFactorRequired reports whether user may not log in without a second
+// factor, either because an admin forced it or because they hold the admin
+// permission and REQUIRE_ADMIN_2FA is on.
+func (h *Controller) twoFactorRequired(user *models.User) (bool, error) {
+	if user.TwoFactorRequired {
+		return true, nil
+	}
+	if !h.cfg.RequireAdminTwoFactor {
+		return false, nil
+	}
+
+	permissions, err := h.store.Role().PermissionsForUser(user.Id)
+	if err != nil {
+		return false, err
+	}
+	for _, permission := range permissions {
+		if permission == models.PermRolesManage {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// @Router /v1/login/2fa [post]
+// @Summary Complete two-factor login
+// @Description API for exchanging the challenge token from the password step and an authenticator or recovery code for a token pair
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body models.TwoFactorLoginRequest true "Challenge token and code"
+// @Success 200 {object} models.LoginResponse
+// @Failure 400 {object} models.ResponseError "Invalid input"
+// @Failure 401 {object} models.ResponseError "Unauthorized"
+// @Failure 429 {object} models.ResponseError "Too many failed attempts"
+// @Failure 500 {object} models.ResponseError "Internal server error"
+func (h *Controller) LoginTwoFactor(c *gin.Context) {
+	var req models.TwoFactorLoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ResponseError{
+			ErrorMessage: "Error while binding JSON: " + err.Error(),
+			ErrorCode:    "Bad Request",
+		})
+		return
+	}
+
+	if (req.Code == "") == (req.RecoveryCode == "") {
+		c.JSON(http.StatusBadRequest, models.ResponseError{
+			ErrorMessage: "Provide either code or recovery_code",
+			ErrorCode:    "Bad Request",
+		})
+		return
+	}
+
+	if h.loginThrottled(c) {
+		return
+	}
+
+	claims, err := h.keys.ParseToken(req.ChallengeToken, auth.TokenChallenge)
+	if err == nil {
+		var revoked bool
+		revoked, err = h.revoker.IsRevoked(c.Request.Context(), claims)
+		if err == nil && revoked {
+			err = auth.ErrInvalidToken
+		}
+	}
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, models.ResponseError{
+			ErrorMessage: "Invalid or expired challenge token",
+			ErrorCode:    "Unauthorized",
+		})
+		return
+	}
+
+	var user *models.User
+	userID, err := uuid.Parse(claims.UserID)
+	if err == nil {
+		user, err = h.store.User().Get(models.RequestId{Id: userID})
+	}
+	if err != nil || user.TOTPEnabledAt == nil {
+		c.JSON(http.StatusUnauthorized, models.ResponseError{
+			ErrorMessage: "Invalid or expired challenge token",
+			ErrorCode:    "Unauthorized",
+		})
+		return
+	}
+
+	if accountLocked(user) {
+		h.registerFailedLogin(c, nil)
+		c.JSON(http.StatusUnauthorized, models.ResponseError{
+			ErrorMessage: "Invalid code",
+			ErrorCode:    "Unauthorized",
+		})
+		return
+	}
+
+	var valid bool
+	if req.RecoveryCode != "" {
+		valid, err = h.store.TwoFactor().ConsumeRecoveryCode(user.Id, hashRecoveryCode(req.RecoveryCode))
+	} else {
+		valid, err = h.checkTOTP(user, req.Code)
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ResponseError{
+			ErrorMessage: "Error while checking code: " + err.Error(),
+			ErrorCode:    "Internal Server Error",
+		})
+		return
+	}
+
+	if !valid {
+		h.registerFailedLogin(c, user)
+		c.JSON(http.StatusUnauthorized, models.ResponseError{
+			ErrorMessage: "Invalid code",
+			ErrorCode:    "Unauthorized",
+		})
+		return
+	}
+
+	h.resetFailedLogins(user)
+
+	// A challenge token completes a single login only.
+	if err := h.revoker.RevokeToken(c.Request.Context(), claims); err != nil {
+		c.JSON(http.StatusInternalServerError, models.ResponseError{
+			ErrorMessage: "Error while revoking token: " + err.Error(),
+			ErrorCode:    "Internal Server Error",
+		})
+		return
+	}
+
+	resp, err := h.createSession(c, user)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ResponseError{
+			ErrorMessage: "Error while generating tokens: " + err.Error(),
+			ErrorCode:    "Internal Server Error",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// @Security ApiKeyAuth
+// @Router /v1/2fa/enroll [post]
+// @Summary Start two-factor enrollment
+// @Description API for generating a new TOTP secret. Two-factor login is enabled only after the secret is confirmed with a code
+// @Tags auth
+// @Produce json
+// @Success 200 {object} models.TwoFactorEnrollResponse
+// @Failure 400 {object} models.ResponseError "Invalid input"
+// @Failure 401 {object} models.ResponseError "Unauthorized"
+// @Failure 500 {object} models.ResponseError "Internal server error"
+func (h *Controller) EnrollTwoFactor(c *gin.Context) {
+	user, ok := h.currentUser(c)
+	if !ok {
+		return
+	}
+
+	if user.TOTPEnabledAt != nil {
+		c.JSON(http.StatusBadRequest, models.ResponseError{
+			ErrorMessage: "Two-factor authentication is already enabled",
+			ErrorCode:    "Bad Request",
+		})
+		return
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err == nil {
+		err = h.store.TwoFactor().SetPendingSecret(user.Id, secret)
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ResponseError{
+			ErrorMessage: "Error while generating secret: " + err.Error(),
+			ErrorCode:    "Internal Server Error",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.TwoFactorEnrollResponse{
+		Secret:     secret,
+		OtpauthURI: totp.URI(h.cfg.TOTPIssuer, user.Username, secret),
+	})
+}
+
+// @Security ApiKeyAuth
+// @Router /v1/2fa/confirm [post]
+// @Summary Confirm two-factor enrollment
+// @Description API for enabling two-factor login with a code from the authenticator app. Returns recovery codes, which are shown only once
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body models.TwoFactorCodeRequest true "Authenticator code"
+// @Success 200 {object} models.TwoFactorConfirmResponse
+// @Failure 400 {object} models.ResponseError "Invalid input"
+// @Failure 401 {object} models.ResponseError "Unauthorized"
+// @Failure 500 {object} models.ResponseError "Internal server error"
+func (h *Controller) ConfirmTwoFactor(c *gin.Context) {
+	var req models.TwoFactorCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ResponseError{
+			ErrorMessage: "Error while binding JSON: " + err.Error(),
+			ErrorCode:    "Bad Request",
+		})
+		return
+	}
+
+	user, ok := h.currentUser(c)
+	if !ok {
+		return
+	}
+
+	if user.TOTPEnabledAt != nil || user.TOTPSecret == nil {
+		c.JSON(http.StatusBadRequest, models.ResponseError{
+			ErrorMessage: "No two-factor enrollment in progress",
+			ErrorCode:    "Bad Request",
+		})
+		return
+	}
+
+	step, valid := totp.Validate(*user.TOTPSecret, req.Code, time.Now())
+	if !valid {
+		c.JSON(http.StatusBadRequest, models.ResponseError{
+			ErrorMessage: "Invalid code",
+			ErrorCode:    "Bad Request",
+		})
+		return
+	}
+
+	codes, hashes, err := generateRecoveryCodes()
+	if err == nil {
+		err = h.store.TwoFactor().Enable(user.Id, step, hashes)
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ResponseError{
+			ErrorMessage: "Error while enabling two-factor authentication: " + err.Error(),
+			ErrorCode:    "Internal Server Error",
+		})
+		return
+	}
+
+	resp := models.TwoFactorConfirmResponse{RecoveryCodes: codes}
+
+	claims, _ := claimsFromContext(c)
+	if claims.TokenType == auth.TokenEnrollment {
+		if err := h.revoker.RevokeToken(c.Request.Context(), claims); err == nil {
+			resp.Login, err = h.createSession(c, user)
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, models.ResponseError{
+				ErrorMessage: "Error while generating tokens: " + err.Error(),
+				ErrorCode:    "Internal Server Error",
+			})
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// @Security ApiKeyAuth
+// @Router /v1/2fa [delete]
+// @Summary Disable two-factor authentication
+// @Description API for turning two-factor login off. Requires the password and a current code
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body models.TwoFactorDisableRequest true "Password and authenticator code"
+// @Success 200 {object} models.ResponseSuccess
+// @Failure 400 {object} models.ResponseError "Invalid input"
+// @Failure 401 {object} models.ResponseError "Unauthorized"
+// @Failure 403 {object} models.ResponseError "Two-factor authentication is mandatory"
+// @Failure 500 {object} models.ResponseError "Internal server error"
+func (h *Controller) DisableTwoFactor(c *gin.Context) {
+	var req models.TwoFactorDisableRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ResponseError{
+			ErrorMessage: "Error while binding JSON: " + err.Error(),
+			ErrorCode:    "Bad Request",
+		})
+		return
+	}
+
+	user, ok := h.currentUser(c)
+	if !ok {
+		return
+	}
+
+	if user.TOTPEnabledAt == nil {
+		c.JSON(http.StatusBadRequest, models.ResponseError{
+			ErrorMessage: "Two-factor authentication is not enabled",
+			ErrorCode:    "Bad Request",
+		})
+		return
+	}
+
+	required, err := h.twoFactorRequired(user)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ResponseError{
+			ErrorMessage: "Error while loading permissions: " + err.Error(),
+			ErrorCode:    "Internal Server Error",
+		})
+		return
+	}
+
+	if required {
+		c.JSON(http.StatusForbidden, models.ResponseError{
+			ErrorMessage: "Two-factor authentication is mandatory for this account",
+			ErrorCode:    "Forbidden",
+		})
+		return
+	}
+
+	isPasswordValid, _, err := h.hasher.Verify(req.Password, user.Password)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ResponseError{
+			ErrorMessage: "Error while checking password: " + err.Error(),
+			ErrorCode:    "Internal Server Error",
+		})
+		return
+	}
+
+	isCodeValid, err := h.checkTOTP(user, req.Code)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ResponseError{
+			ErrorMessage: "Error while checking code: " + err.Error(),
+			ErrorCode:    "Internal Server Error",
+		})
+		return
+	}
+
+	if !isPasswordValid || !isCodeValid {
+		c.JSON(http.StatusUnauthorized, models.ResponseError{
+			ErrorMessage: "Invalid password or code",
+			ErrorCode:    "Unauthorized",
+		})
+		return
+	}
+
+	if err := h.store.TwoFactor().Disable(user.Id); err != nil {
+		c.JSON(http.StatusInternalServerError, models.ResponseError{
+			ErrorMessage: "Error while disabling two-factor authentication: " + err.Error(),
+			ErrorCode:    "Internal Server Error",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ResponseSuccess{
+		Message: "Two-factor authentication disabled",
+	})
+}
+
+// @Security ApiKeyAuth
+// @Router /v1/admin/users/{user_id}/2fa [put]
+// @Summary Require two-factor authentication
+// @Description API for forcing a user to set up two-factor authentication before they can log in again
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param user_id path string true "User ID"
+// @Param request body models.TwoFactorRequirement true "Requirement"
+// @Success 200 {object} models.ResponseSuccess
+// @Failure 400 {object} models.ResponseError "Invalid input"
+// @Failure 403 {object} models.ResponseError "Forbidden"
+// @Failure 404 {object} models.ResponseError "Not found"
+// @Failure 500 {object} models.ResponseError "Internal server error"
+func (h *Controller) SetTwoFactorRequired(c *gin.Context) {
+	var req models.TwoFactorRequirement
+	userID, err := uuid.Parse(c.Param("user_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ResponseError{
+			ErrorMessage: "Invalid UUID format: " + err.Error(),
+			ErrorCode:    "Bad Request",
+		})
+		return
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ResponseError{
+			ErrorMessage: "Error while binding JSON: " + err.Error(),
+			ErrorCode:    "Bad Request",
+		})
+		return
+	}
+
+	if _, err := h.store.User().Get(models.RequestId{Id: userID}); err != nil {
+		respondLookupError(c, err, "User not found", "Error while retrieving the user: ")
+		return
+	}
+
+	if err := h.store.TwoFactor().SetRequired(userID, req.Required); err != nil {
+		c.JSON(http.StatusInternalServerError, models.ResponseError{
+			ErrorMessage: "Error while updating the user: " + err.Error(),
+			ErrorCode:    "Internal Server Error",
+		})
+		return
+	}
+
+	// Sessions that predate the requirement were created without a second
+	// factor, so they are ended.
+	if req.Required {
+		if err := h.logoutEverywhere(c.Request.Context(), userID); err != nil {
+			c.JSON(http.StatusInternalServerError, models.ResponseError{
+				ErrorMessage: "Error while revoking tokens: " + err.Error(),
+				ErrorCode:    "Internal Server Error",
+			})
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, models.ResponseSuccess{
+		Message: "Two-factor requirement updated",
+	})
+}
+
+// checkTOTP validates code and makes sure it has not been used before.
+func (h *Controller) checkTOTP(user *models.User, code string) (bool, error) {
+	if user.TOTPSecret == nil {
+		return false, nil
+	}
+
+	step, valid := totp.Validate(*user.TOTPSecret, code, time.Now())
+	if !valid || step <= user.TOTPLastStep {
+		return false, nil
+	}
+
+	return h.store.TwoFactor().UseStep(user.Id, step)
+}
+
+func (h *Controller) currentUser(c *gin.Context) (*models.User, bool) {
+	userID, err := uuid.Parse(c.GetString("userID"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ResponseError{
+			ErrorMessage: "Invalid UUID format from token: " + err.Error(),
+			ErrorCode:    "Bad Request",
+		})
+		return nil, false
+	}
+
+	user, err := h.store.User().Get(models.RequestId{Id: userID})
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, models.ResponseError{
+			ErrorMessage: "User not found",
+			ErrorCode:    "Unauthorized",
+		})
+		return nil, false
+	}
+
+	return user, true
+}
+
+var recoveryCodeEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// generateRecoveryCodes returns recovery codes in the form "xxxxx-xxxxx"
+// together with the hashes that are stored in their place.
+func generateRecoveryCodes() ([]string, []string, error) {
+	codes := make([]string, 0, recoveryCodeCount)
+	hashes := make([]string, 0, recoveryCodeCount)
+	for i := 0; i < recoveryCodeCount; i++ {
+		buf := make([]byte, 7)
+		if _, err := rand.Read(buf); err != nil {
+			return nil, nil, err
+		}
+
+		raw := strings.ToLower(recoveryCodeEncoding.EncodeToString(buf))[:10]
+		codes = append(codes, raw[:5]+"-"+raw[5:])
+		hashes = append(hashes, hashRecoveryCode(raw))
+	}
+	return codes, hashes, nil
+}
+
+func hashRecoveryCode(code string) string {
+	normalized := strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+	return auth.HashToken(normalized)
+}
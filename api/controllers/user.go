@@ -1,8 +1,10 @@
 package controllers
 
 import (
+	"fmt"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"log"
 	"net/http"
 	"project/etc"
 	"project/models"
@@ -11,7 +13,7 @@ import (
 // @Security ApiKeyAuth
 // @Router /v1/users [post]
 // @Summary Create a user
-// @Description API for creating a new user
+// @Description API for creating a new user. A verification token is sent to the given e-mail address
 // @Tags user
 // @Accept json
 // @Produce json
@@ -29,7 +31,28 @@ func (h *Controller) CreateUser(c *gin.Context) {
 		return
 	}
 
-	hashPassword, err := etc.GeneratePasswordHash(userModel.Password)
+	if etc.IsEmail(userModel.Username) {
+		c.JSON(http.StatusBadRequest, models.ResponseError{
+			ErrorMessage: "Username must not contain '@'",
+			ErrorCode:    "Bad Request",
+		})
+		return
+	}
+
+	if !h.validatePassword(c, userModel.Password) {
+		return
+	}
+
+	email := etc.NormalizeEmail(userModel.Email)
+	if _, err := h.store.User().GetByEmail(email); err == nil {
+		c.JSON(http.StatusBadRequest, models.ResponseError{
+			ErrorMessage: "Email is already in use",
+			ErrorCode:    "Bad Request",
+		})
+		return
+	}
+
+	hashPassword, err := h.hasher.Hash(userModel.Password)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, models.ResponseError{
 			ErrorMessage: "Error while hashing password" + err.Error(),
@@ -41,7 +64,8 @@ func (h *Controller) CreateUser(c *gin.Context) {
 		Name:         userModel.Name,
 		Bio:          userModel.Bio,
 		Username:     userModel.Username,
-		Password:     string(hashPassword),
+		Email:        &email,
+		Password:     hashPassword,
 		ProfileImage: userModel.ProfileImage,
 	}
 
@@ -54,6 +78,10 @@ func (h *Controller) CreateUser(c *gin.Context) {
 		return
 	}
 
+	if err := h.sendEmailVerification(c.Request.Context(), &user); err != nil {
+		log.Printf("Failed to send verification message: %v", err)
+	}
+
 	c.JSON(http.StatusOK, models.ResponseId{Id: id})
 }
 
@@ -97,6 +125,14 @@ func (h *Controller) UpdateUser(c *gin.Context) {
 		return
 	}
 
+	if etc.IsEmail(userModel.Username) {
+		c.JSON(http.StatusBadRequest, models.ResponseError{
+			ErrorMessage: "Username must not contain '@'",
+			ErrorCode:    "Bad Request",
+		})
+		return
+	}
+
 	user := models.User{
 		Id:           userId,
 		Name:         userModel.Name,
@@ -121,18 +157,25 @@ func (h *Controller) UpdateUser(c *gin.Context) {
 // @Security ApiKeyAuth
 // @Router /v1/users/{user_id} [delete]
 // @Summary Delete a user
-// @Description API for deleting a user
+// @Description API for deleting a user. The account and its content are hidden at once and permanently deleted after a grace period. Until then, users who deleted their own account can restore it by logging in
 // @Tags user
 // @Param user_id path string true "User ID"
 // @Success 200 {object} models.ResponseSuccess
 // @Failure 400 {object} models.ResponseError "Invalid input"
+// @Failure 403 {object} models.ResponseError "Not the owner of the account"
+// @Failure 404 {object} models.ResponseError "User not found"
 // @Failure 500 {object} models.ResponseError "Internal server error"
 func (h *Controller) DeleteUser(c *gin.Context) {
 	idStr := c.Param("user_id")
 	id := uuid.MustParse(idStr)
 
-	err := h.store.User().Delete(models.RequestId{Id: id})
-	if err != nil {
+	// Only a deactivation by the owner can be undone by logging in.
+	var by *uuid.UUID
+	if actor, err := uuid.Parse(c.GetString("userID")); err == nil && actor != id {
+		by = &actor
+	}
+
+	if err := h.store.User().Deactivate(id, by); err != nil {
 		c.JSON(http.StatusInternalServerError, models.ResponseError{
 			ErrorMessage: "Error while deleting the user: " + err.Error(),
 			ErrorCode:    "Internal Server Error",
@@ -140,19 +183,29 @@ func (h *Controller) DeleteUser(c *gin.Context) {
 		return
 	}
 
+	if err := h.logoutEverywhere(c.Request.Context(), id); err != nil {
+		c.JSON(http.StatusInternalServerError, models.ResponseError{
+			ErrorMessage: "Error while revoking tokens: " + err.Error(),
+			ErrorCode:    "Internal Server Error",
+		})
+		return
+	}
+
 	c.JSON(http.StatusOK, models.ResponseSuccess{
-		Message: "User deleted successfully",
+		Message: fmt.Sprintf("User deactivated, the account will be deleted permanently in %s", h.cfg.AccountDeletionGrace),
 	})
 }
 
 // @Security ApiKeyAuth
 // @Router /v1/users/{user_id} [get]
 // @Summary Get a user by ID
-// @Description API for retrieving a user by ID
+// @Description API for retrieving a user by ID. Authentication is optional and adds viewer-specific fields
 // @Tags user
 // @Param user_id path string true "User ID"
 // @Success 200 {object} models.User
 // @Failure 400 {object} models.ResponseError "Invalid input"
+// @Failure 401 {object} models.ResponseError "Invalid token"
+// @Failure 404 {object} models.ResponseError "User not found"
 // @Failure 500 {object} models.ResponseError "Internal server error"
 func (h *Controller) GetUser(c *gin.Context) {
 	idStr := c.Param("user_id")
@@ -167,6 +220,30 @@ func (h *Controller) GetUser(c *gin.Context) {
 		return
 	}
 
+	hidden := user.DeactivatedAt != nil
+	viewer := viewerID(c)
+	if !hidden && viewer != uuid.Nil {
+		hidden, err = h.store.Block().IsBlocking(user.Id, viewer)
+		if err == nil {
+			err = h.hydrateUser(viewer, user)
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, models.ResponseError{
+				ErrorMessage: "Error while retrieving the user: " + err.Error(),
+				ErrorCode:    "Internal Server Error",
+			})
+			return
+		}
+	}
+
+	if hidden {
+		c.JSON(http.StatusNotFound, models.ResponseError{
+			ErrorMessage: "User not found",
+			ErrorCode:    "Not Found",
+		})
+		return
+	}
+
 	c.JSON(http.StatusOK, user)
 }
 
@@ -0,0 +1,42 @@
+package controllers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"project/models"
+)
+
+// viewerID returns the authenticated user of a request that went through
+// OptionalAuth, or uuid.Nil for anonymous requests.
+func viewerID(c *gin.Context) uuid.UUID {
+	id, err := uuid.Parse(c.GetString("userID"))
+	if err != nil {
+		return uuid.Nil
+	}
+	return id
+}
+
+// hydrateUser fills in the viewer-specific fields of user.
+func (h *Controller) hydrateUser(viewer uuid.UUID, user *models.User) error {
+	if viewer == uuid.Nil || viewer == user.Id {
+		return nil
+	}
+
+	following, err := h.store.Follow().IsFollowing(viewer, user.Id)
+	if err != nil {
+		return err
+	}
+	blocked, err := h.store.Block().IsBlocking(viewer, user.Id)
+	if err != nil {
+		return err
+	}
+	muted, err := h.store.Mute().IsMuting(viewer, user.Id)
+	if err != nil {
+		return err
+	}
+
+	user.Following = &following
+	user.BlockedByMe = &blocked
+	user.MutedByMe = &muted
+	return nil
+}
@@ -7,12 +7,13 @@ import (
 	"project/api/controllers"
 	"project/api/middleware"
 	_ "project/docs" //for swagger
+	"project/models"
 )
 
 // @securityDefinitions.apikey ApiKeyAuth
 // @in header
 // @name Authorization
-func Construct(cont controllers.Controller) *gin.Engine {
+func Construct(cont controllers.Controller, mw *middleware.Middleware) *gin.Engine {
 	r := gin.New()
 
 	r.Static("/images", "./public/images")
@@ -22,30 +23,95 @@ func Construct(cont controllers.Controller) *gin.Engine {
 
 	r.GET("/", func(c *gin.Context) { c.JSON(200, gin.H{"message": "pong"}) })
 
+	r.GET("/.well-known/jwks.json", cont.JWKS)
+
 	api := r.Group("/v1")
 	{
 		//login
 		api.POST("login", cont.LoginUser)
+		api.POST("/login/2fa", cont.LoginTwoFactor)
+		api.POST("/token/refresh", cont.RefreshToken)
+		api.POST("/logout", mw.AuthMiddleware(), cont.Logout)
+		api.POST("/logout/all", mw.AuthMiddleware(), cont.LogoutAll)
+		api.POST("/password/forgot", cont.ForgotPassword)
+		api.POST("/password/reset", cont.ResetPassword)
+		api.POST("/2fa/enroll", mw.EnrollmentAuth(), cont.EnrollTwoFactor)
+		api.POST("/2fa/confirm", mw.EnrollmentAuth(), cont.ConfirmTwoFactor)
+		api.DELETE("/2fa", mw.AuthMiddleware(), cont.DisableTwoFactor)
+
+		//sessions
+		api.GET("/sessions", mw.AuthMiddleware(), cont.GetAllSessions)
+		api.GET("/sessions/history", mw.AuthMiddleware(), cont.GetLoginHistory)
+		api.DELETE("/sessions/:session_id", mw.AuthMiddleware(), cont.RevokeSession)
+
+		//personal access tokens
+		api.POST("/tokens", mw.AuthMiddleware(), cont.CreatePersonalToken)
+		api.GET("/tokens", mw.AuthMiddleware(), cont.GetAllPersonalTokens)
+		api.DELETE("/tokens/:token_id", mw.AuthMiddleware(), cont.RevokePersonalToken)
 
 		//user endpoints
 		api.POST("/users", cont.CreateUser)
-		api.PUT("/users", middleware.AuthMiddleware(), cont.UpdateUser)
-		api.DELETE("/users/:user_id", middleware.AuthMiddleware(), cont.DeleteUser)
-		api.GET("/users/:user_id", cont.GetUser)
+		api.PUT("/users", mw.AuthMiddleware(), cont.UpdateUser)
+		api.PUT("/users/password", mw.AuthMiddleware(), cont.ChangePassword)
+		api.POST("/users/email/verify", cont.VerifyEmail)
+		api.POST("/users/email/resend", mw.AuthMiddleware(), cont.ResendEmailVerification)
+		api.DELETE("/users/:user_id", mw.AuthMiddleware(), mw.RequireAccountOwner(), cont.DeleteUser)
+		api.GET("/users/:user_id", mw.OptionalAuth(), cont.GetUser)
+		api.GET("/users/:user_id/mentions", mw.OptionalAuth(), cont.GetUserMentions)
 		api.GET("/users", cont.GetAllUsers)
-		api.POST("/users/follow/:user_id", middleware.AuthMiddleware(), cont.FollowUser)
-		api.DELETE("/users/unfollow/:user_id", middleware.AuthMiddleware(), cont.UnfollowUser)
+		api.POST("/users/follow/:user_id", mw.AuthMiddleware(models.ScopeFollowsWrite), cont.FollowUser)
+		api.DELETE("/users/unfollow/:user_id", mw.AuthMiddleware(models.ScopeFollowsWrite), cont.UnfollowUser)
+		api.POST("/users/block/:user_id", mw.AuthMiddleware(), cont.BlockUser)
+		api.DELETE("/users/unblock/:user_id", mw.AuthMiddleware(), cont.UnblockUser)
+		api.POST("/users/mute/:user_id", mw.AuthMiddleware(), cont.MuteUser)
+		api.DELETE("/users/unmute/:user_id", mw.AuthMiddleware(), cont.UnmuteUser)
 
 		//tweet endpoints
-		api.POST("/tweets", middleware.AuthMiddleware(), cont.CreateTweet)
-		api.PUT("/tweets/:tweet_id", middleware.AuthMiddleware(), cont.UpdateTweet)
-		api.DELETE("/tweets/:tweet_id", middleware.AuthMiddleware(), cont.DeleteTweet)
-		api.GET("/tweets/:tweet_id", cont.GetTweet)
-		api.GET("/tweets", cont.GetAllTweets)
-		api.GET("/tweets/feed", middleware.AuthMiddleware(), cont.GetTweetsFeed)
-		api.POST("/tweets/like/:tweet_id", middleware.AuthMiddleware(), cont.LikeTweet)
-		api.DELETE("/tweets/unlike/:tweet_id", middleware.AuthMiddleware(), cont.UnlikeTweet)
-		api.POST("/tweets/retweet/:tweet_id", middleware.AuthMiddleware(), cont.Retweet)
+		api.POST("/tweets", mw.AuthMiddleware(models.ScopeTweetsWrite), mw.RequirePermission(models.PermTweetsWrite), mw.RequireVerifiedEmail(), cont.CreateTweet)
+		api.PUT("/tweets/:tweet_id", mw.AuthMiddleware(models.ScopeTweetsWrite), mw.RequirePermission(models.PermTweetsWrite), mw.RequireTweetOwner(), cont.UpdateTweet)
+		api.DELETE("/tweets/:tweet_id", mw.AuthMiddleware(models.ScopeTweetsWrite), mw.RequireTweetOwner(), cont.DeleteTweet)
+		api.GET("/tweets/:tweet_id", mw.OptionalAuth(), cont.GetTweet)
+		api.GET("/tweets", mw.OptionalAuth(), cont.GetAllTweets)
+		api.GET("/tweets/feed", mw.AuthMiddleware(models.ScopeRead), cont.GetTweetsFeed)
+		api.POST("/tweets/like/:tweet_id", mw.AuthMiddleware(models.ScopeLikesWrite), cont.LikeTweet)
+		api.DELETE("/tweets/unlike/:tweet_id", mw.AuthMiddleware(models.ScopeLikesWrite), cont.UnlikeTweet)
+		api.POST("/tweets/retweet/:tweet_id", mw.AuthMiddleware(models.ScopeTweetsWrite), mw.RequirePermission(models.PermTweetsWrite), mw.RequireVerifiedEmail(), cont.Retweet)
+		api.DELETE("/tweets/retweet/:tweet_id", mw.AuthMiddleware(models.ScopeTweetsWrite), cont.Unretweet)
+		api.GET("/tweets/:tweet_id/retweeters", mw.OptionalAuth(), cont.GetRetweeters)
+		api.GET("/tweets/:tweet_id/history", mw.OptionalAuth(), cont.GetTweetHistory)
+		api.POST("/tweets/:tweet_id/poll/votes", mw.AuthMiddleware(models.ScopeTweetsWrite), cont.VotePoll)
+		api.POST("/tweets/quote/:tweet_id", mw.AuthMiddleware(models.ScopeTweetsWrite), mw.RequirePermission(models.PermTweetsWrite), mw.RequireVerifiedEmail(), cont.QuoteTweet)
+		api.POST("/tweets/reply/:tweet_id", mw.AuthMiddleware(models.ScopeTweetsWrite), mw.RequirePermission(models.PermTweetsWrite), mw.RequireVerifiedEmail(), cont.ReplyTweet)
+		api.GET("/tweets/conversation/:tweet_id", mw.OptionalAuth(), cont.GetConversation)
+		api.GET("/tweets/replies/:tweet_id", mw.OptionalAuth(), cont.GetReplies)
+		api.GET("/tweets/scheduled", mw.AuthMiddleware(models.ScopeRead), cont.GetScheduledTweets)
+		api.PUT("/tweets/scheduled/:scheduled_id", mw.AuthMiddleware(models.ScopeTweetsWrite), cont.RescheduleTweet)
+		api.DELETE("/tweets/scheduled/:scheduled_id", mw.AuthMiddleware(models.ScopeTweetsWrite), cont.CancelScheduledTweet)
+
+		//draft endpoints
+		api.POST("/drafts", mw.AuthMiddleware(models.ScopeTweetsWrite), cont.CreateDraft)
+		api.GET("/drafts", mw.AuthMiddleware(models.ScopeRead), cont.GetDrafts)
+		api.PUT("/drafts/:draft_id", mw.AuthMiddleware(models.ScopeTweetsWrite), cont.UpdateDraft)
+		api.DELETE("/drafts/:draft_id", mw.AuthMiddleware(models.ScopeTweetsWrite), cont.DeleteDraft)
+		api.POST("/drafts/:draft_id/publish", mw.AuthMiddleware(models.ScopeTweetsWrite), mw.RequirePermission(models.PermTweetsWrite), mw.RequireVerifiedEmail(), cont.PublishDraft)
+
+		//hashtag endpoints
+		api.GET("/hashtags/:tag/tweets", mw.OptionalAuth(), cont.GetHashtagTweets)
+		api.GET("/trends", cont.GetTrends)
+
+		//admin endpoints
+		admin := api.Group("/admin", mw.AuthMiddleware())
+		{
+			roles := admin.Group("", mw.RequirePermission(models.PermRolesManage))
+			roles.GET("/roles", cont.GetAllRoles)
+			roles.POST("/roles", cont.CreateRole)
+			roles.GET("/users/:user_id/roles", cont.GetUserRoles)
+			roles.POST("/users/:user_id/roles", cont.AssignRole)
+			roles.DELETE("/users/:user_id/roles/:role", cont.RevokeRole)
+			roles.PUT("/users/:user_id/2fa", cont.SetTwoFactorRequired)
+
+			admin.GET("/lockouts", mw.RequirePermission(models.PermAuditRead), cont.GetAllLockouts)
+		}
 	}
 
 	url := ginSwagger.URL("swagger/doc.json")
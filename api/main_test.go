@@ -0,0 +1,202 @@
+package api
+
+import (
+	"bytes"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"net/http"
+	"net/http/httptest"
+	"project/api/controllers"
+	"project/api/middleware"
+	"project/config"
+	"project/database/cache"
+	"project/database/storetest"
+	auth "project/etc/jwt"
+	"project/etc/mailer"
+	"project/models"
+	"testing"
+	"time"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+type testServer struct {
+	t      *testing.T
+	store  *storetest.Store
+	keys   *auth.KeyManager
+	router *gin.Engine
+}
+
+func newTestServer(t *testing.T) *testServer {
+	t.Helper()
+
+	store := storetest.New()
+	cfg := config.Load()
+	cfg.JWT = auth.KeyConfig{Algorithm: auth.AlgEdDSA, Rotation: 24 * time.Hour, Grace: time.Hour}
+	cfg.RequireVerifiedEmail = false
+
+	keys, err := auth.NewKeyManager(store.SigningKey(), cfg.JWT)
+	if err != nil {
+		t.Fatal(err)
+	}
+	kv := cache.NewMemory()
+	revoker := auth.NewRevoker(kv, cfg.AccessTokenTTL)
+
+	cont := controllers.NewController(store, cfg, kv, revoker, keys, mailer.NewOutbox(t.TempDir()))
+	mw := middleware.New(store, cfg, revoker, keys)
+	return &testServer{t: t, store: store, keys: keys, router: Construct(*cont, mw)}
+}
+
+// user creates a user holding the permissions and returns it with an access
+// token of a new session.
+func (s *testServer) user(name string, permissions ...string) (*models.User, string) {
+	s.t.Helper()
+
+	user := s.store.AddUser(name)
+	s.store.Grant(user.Id, permissions...)
+
+	session := models.Session{UserID: user.Id, LastSeenAt: time.Now()}
+	if err := s.store.Session().Create(&session, &models.LoginEvent{}); err != nil {
+		s.t.Fatal(err)
+	}
+	token, err := s.keys.GenerateToken(user.Id.String(), session.Id.String(), time.Minute)
+	if err != nil {
+		s.t.Fatal(err)
+	}
+	return user, token
+}
+
+func (s *testServer) do(method, path, token, body string) *httptest.ResponseRecorder {
+	s.t.Helper()
+
+	req := httptest.NewRequest(method, path, bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	w := httptest.NewRecorder()
+	s.router.ServeHTTP(w, req)
+	return w
+}
+
+func TestTweetOwnership(t *testing.T) {
+	routes := []struct {
+		method string
+		body   string
+	}{
+		{http.MethodPut, `{"content": "edited"}`},
+		{http.MethodDelete, ""},
+	}
+
+	for _, route := range routes {
+		t.Run(route.method, func(t *testing.T) {
+			tests := []struct {
+				name        string
+				permissions []string
+				own         bool
+				missing     bool
+				want        int
+			}{
+				{name: "owner", permissions: []string{models.PermTweetsWrite}, own: true, want: http.StatusOK},
+				{name: "non-owner", permissions: []string{models.PermTweetsWrite}, want: http.StatusForbidden},
+				{name: "moderator", permissions: []string{models.PermTweetsWrite, models.PermTweetsModerate}, want: http.StatusOK},
+				{name: "missing tweet", permissions: []string{models.PermTweetsWrite, models.PermTweetsModerate}, missing: true, want: http.StatusNotFound},
+			}
+			for _, tt := range tests {
+				t.Run(tt.name, func(t *testing.T) {
+					s := newTestServer(t)
+					owner, ownerToken := s.user("owner", models.PermTweetsWrite)
+					_, token := s.user("actor", tt.permissions...)
+					if tt.own {
+						token = ownerToken
+					}
+
+					tweetID := s.store.AddTweet(owner.Id, "original").Id
+					if tt.missing {
+						tweetID = uuid.New()
+					}
+
+					w := s.do(route.method, "/v1/tweets/"+tweetID.String(), token, route.body)
+					if w.Code != tt.want {
+						t.Errorf("got %d, want %d: %s", w.Code, tt.want, w.Body.String())
+					}
+
+					if tt.want == http.StatusForbidden {
+						tweet, err := s.store.Tweet().Get(models.RequestId{Id: tweetID})
+						if err != nil || tweet.Content != "original" {
+							t.Errorf("tweet was modified by a non-owner: %+v, %v", tweet, err)
+						}
+					}
+				})
+			}
+		})
+	}
+}
+
+func TestTweetOwnershipRequiresAuthentication(t *testing.T) {
+	s := newTestServer(t)
+	owner, _ := s.user("owner", models.PermTweetsWrite)
+	tweet := s.store.AddTweet(owner.Id, "original")
+
+	for _, method := range []string{http.MethodPut, http.MethodDelete} {
+		if w := s.do(method, "/v1/tweets/"+tweet.Id.String(), "", `{"content": "edited"}`); w.Code != http.StatusUnauthorized {
+			t.Errorf("%s without a token: got %d, want %d", method, w.Code, http.StatusUnauthorized)
+		}
+	}
+}
+
+func TestUpdateTweetRequiresWritePermission(t *testing.T) {
+	s := newTestServer(t)
+	owner, token := s.user("owner")
+	tweet := s.store.AddTweet(owner.Id, "original")
+
+	if w := s.do(http.MethodPut, "/v1/tweets/"+tweet.Id.String(), token, `{"content": "edited"}`); w.Code != http.StatusForbidden {
+		t.Errorf("got %d, want %d: %s", w.Code, http.StatusForbidden, w.Body.String())
+	}
+}
+
+func TestAccountOwnership(t *testing.T) {
+	tests := []struct {
+		name        string
+		permissions []string
+		own         bool
+		missing     bool
+		want        int
+	}{
+		{name: "owner", own: true, want: http.StatusOK},
+		{name: "non-owner", want: http.StatusForbidden},
+		{name: "moderator", permissions: []string{models.PermUsersModerate}, want: http.StatusOK},
+		{name: "tweet moderator", permissions: []string{models.PermTweetsModerate}, want: http.StatusForbidden},
+		{name: "missing user", permissions: []string{models.PermUsersModerate}, missing: true, want: http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestServer(t)
+			owner, ownerToken := s.user("owner")
+			_, token := s.user("actor", tt.permissions...)
+			if tt.own {
+				token = ownerToken
+			}
+
+			userID := owner.Id
+			if tt.missing {
+				userID = uuid.New()
+			}
+
+			w := s.do(http.MethodDelete, "/v1/users/"+userID.String(), token, "")
+			if w.Code != tt.want {
+				t.Errorf("got %d, want %d: %s", w.Code, tt.want, w.Body.String())
+			}
+
+			deactivated, err := s.store.User().Get(models.RequestId{Id: owner.Id})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if wantDeactivated := tt.want == http.StatusOK; (deactivated.DeactivatedAt != nil) != wantDeactivated {
+				t.Errorf("account deactivated: %v, want %v", deactivated.DeactivatedAt != nil, wantDeactivated)
+			}
+		})
+	}
+}
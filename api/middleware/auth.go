@@ -1,14 +1,54 @@
 package middleware
 
 import (
-	"github.com/dgrijalva/jwt-go"
 	"github.com/gin-gonic/gin"
 	"net/http"
+	"project/config"
+	"project/database"
 	auth "project/etc/jwt"
+	"project/models"
 	"strings"
 )
 
-func AuthMiddleware() gin.HandlerFunc {
+type Middleware struct {
+	store   database.IStore
+	cfg     config.Config
+	revoker *auth.Revoker
+	keys    *auth.KeyManager
+}
+
+func New(store database.IStore, cfg config.Config, revoker *auth.Revoker, keys *auth.KeyManager) *Middleware {
+	return &Middleware{store: store, cfg: cfg, revoker: revoker, keys: keys}
+}
+
+// AuthMiddleware accepts access tokens and personal access tokens. The latter
+// are only let through when they were granted every scope listed; without
+// scopes the route is closed to personal access tokens altogether.
+func (m *Middleware) AuthMiddleware(scopes ...string) gin.HandlerFunc {
+	return m.authenticate([]string{auth.TokenAccess}, scopes)
+}
+
+// OptionalAuth lets anonymous requests through, so public endpoints can
+// personalize their response when a token is sent. A token that is sent
+// must be valid; personal access tokens need the read scope.
+func (m *Middleware) OptionalAuth() gin.HandlerFunc {
+	authenticate := m.authenticate([]string{auth.TokenAccess}, []string{models.ScopeRead})
+	return func(c *gin.Context) {
+		if c.GetHeader("Authorization") == "" {
+			c.Next()
+			return
+		}
+		authenticate(c)
+	}
+}
+
+// EnrollmentAuth also accepts the restricted token handed out to users who
+// must set up two-factor authentication before they can log in.
+func (m *Middleware) EnrollmentAuth() gin.HandlerFunc {
+	return m.authenticate([]string{auth.TokenAccess, auth.TokenEnrollment}, nil)
+}
+
+func (m *Middleware) authenticate(allowed []string, scopes []string) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
@@ -18,19 +58,37 @@ func AuthMiddleware() gin.HandlerFunc {
 		}
 
 		tokenString := strings.TrimPrefix(authHeader, "Bearer ")
-		claims := &auth.Claims{}
-		token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
-			return auth.JwtSecret, nil
-		})
+		if strings.HasPrefix(tokenString, auth.PersonalTokenPrefix) {
+			m.authenticatePersonalToken(c, tokenString, scopes)
+			return
+		}
 
-		if err != nil || !token.Valid {
+		claims, err := m.keys.ParseToken(tokenString, allowed...)
+		if err != nil {
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired token"})
 			c.Abort()
 			return
 		}
 
+		revoked, err := m.revoker.IsRevoked(c.Request.Context(), claims)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error while checking token revocation"})
+			c.Abort()
+			return
+		}
+
+		if revoked {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Token has been revoked"})
+			c.Abort()
+			return
+		}
+
+		if claims.SessionID != "" && !m.checkSession(c, claims.SessionID) {
+			return
+		}
+
 		c.Set("userID", claims.UserID)
-		c.Set("role", claims.Role)
+		c.Set("claims", claims)
 		c.Next()
 	}
 }
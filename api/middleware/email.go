@@ -0,0 +1,42 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"net/http"
+	"project/models"
+)
+
+// RequireVerifiedEmail must run after AuthMiddleware. When the
+// REQUIRE_VERIFIED_EMAIL setting is on it rejects users who have not
+// verified their e-mail address yet; otherwise it does nothing.
+func (m *Middleware) RequireVerifiedEmail() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !m.cfg.RequireVerifiedEmail {
+			c.Next()
+			return
+		}
+
+		userID, err := uuid.Parse(c.GetString("userID"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid UUID format from token"})
+			c.Abort()
+			return
+		}
+
+		user, err := m.store.User().Get(models.RequestId{Id: userID})
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+			c.Abort()
+			return
+		}
+
+		if user.EmailVerifiedAt == nil {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Verify your e-mail address first"})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
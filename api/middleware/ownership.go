@@ -0,0 +1,94 @@
+package middleware
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"net/http"
+	"project/models"
+)
+
+// RequireTweetOwner must run after AuthMiddleware. It lets the request
+// through only when the tweet in the :tweet_id path parameter belongs to the
+// authenticated user or the user may moderate tweets. The loaded tweet is
+// stored in the context under "tweet".
+func (m *Middleware) RequireTweetOwner() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tweetID, err := uuid.Parse(c.Param("tweet_id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid UUID format: " + err.Error()})
+			c.Abort()
+			return
+		}
+
+		tweet, err := m.store.Tweet().Get(models.RequestId{Id: tweetID})
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Tweet not found"})
+			} else {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Error while retrieving the tweet"})
+			}
+			c.Abort()
+			return
+		}
+
+		if !m.authorizeOwner(c, tweet.UserID, models.PermTweetsModerate) {
+			return
+		}
+
+		c.Set("tweet", tweet)
+		c.Next()
+	}
+}
+
+// RequireAccountOwner must run after AuthMiddleware. It lets the request
+// through only when the :user_id path parameter is the authenticated user or
+// the user may moderate accounts.
+func (m *Middleware) RequireAccountOwner() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := uuid.Parse(c.Param("user_id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid UUID format: " + err.Error()})
+			c.Abort()
+			return
+		}
+
+		if _, err := m.store.User().Get(models.RequestId{Id: userID}); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+			} else {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Error while retrieving the user"})
+			}
+			c.Abort()
+			return
+		}
+
+		if !m.authorizeOwner(c, userID, models.PermUsersModerate) {
+			return
+		}
+
+		c.Next()
+	}
+}
+
+func (m *Middleware) authorizeOwner(c *gin.Context, ownerID uuid.UUID, override string) bool {
+	if ownerID.String() == c.GetString("userID") {
+		return true
+	}
+
+	granted, err := m.Permissions(c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error while loading permissions"})
+		c.Abort()
+		return false
+	}
+
+	if !granted[override] {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You are not allowed to modify this resource"})
+		c.Abort()
+		return false
+	}
+
+	return true
+}
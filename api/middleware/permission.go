@@ -0,0 +1,57 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"net/http"
+)
+
+// RequirePermission must run after AuthMiddleware. It aborts with 403 unless
+// the user holds every listed permission through one of their roles.
+func (m *Middleware) RequirePermission(permissions ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		granted, err := m.Permissions(c)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error while loading permissions"})
+			c.Abort()
+			return
+		}
+
+		for _, permission := range permissions {
+			if !granted[permission] {
+				c.JSON(http.StatusForbidden, gin.H{"error": "Missing permission: " + permission})
+				c.Abort()
+				return
+			}
+		}
+
+		c.Next()
+	}
+}
+
+// Permissions returns the permissions of the authenticated user. They are
+// read from the database once per request, never from the token, so role
+// changes apply to tokens that are already issued.
+func (m *Middleware) Permissions(c *gin.Context) (map[string]bool, error) {
+	if cached, ok := c.Get("permissions"); ok {
+		return cached.(map[string]bool), nil
+	}
+
+	userID, err := uuid.Parse(c.GetString("userID"))
+	if err != nil {
+		return nil, err
+	}
+
+	names, err := m.store.Role().PermissionsForUser(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	granted := make(map[string]bool, len(names))
+	for _, name := range names {
+		granted[name] = true
+	}
+
+	c.Set("permissions", granted)
+	return granted, nil
+}
@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"log"
+	"net/http"
+	"time"
+)
+
+// checkSession rejects access tokens of sessions that were revoked, so that
+// logging a device out takes effect before its access token expires.
+func (m *Middleware) checkSession(c *gin.Context, sid string) bool {
+	sessionID, err := uuid.Parse(sid)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired token"})
+		c.Abort()
+		return false
+	}
+
+	session, err := m.store.Session().Get(sessionID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Session has been revoked"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error while checking session"})
+		}
+		c.Abort()
+		return false
+	}
+
+	if session.RevokedAt != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Session has been revoked"})
+		c.Abort()
+		return false
+	}
+
+	now := time.Now()
+	if now.Sub(session.LastSeenAt) >= lastUsedResolution {
+		if err := m.store.Session().Seen(session.Id, now); err != nil {
+			log.Printf("Failed to update last use of session %s: %v", session.Id, err)
+		}
+	}
+	return true
+}
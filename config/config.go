@@ -0,0 +1,153 @@
+package config
+
+import (
+	"os"
+	auth "project/etc/jwt"
+	"project/etc/mailer"
+	"project/etc/password"
+	"strconv"
+	"time"
+)
+
+type Config struct {
+	RedisURL         string
+	AccessTokenTTL   time.Duration
+	RefreshTokenTTL  time.Duration
+	PasswordResetTTL time.Duration
+	Mail             mailer.Config
+	JWT              auth.KeyConfig
+	Password         password.Config
+
+	EmailVerificationTTL time.Duration
+	// RequireVerifiedEmail stops accounts with an unverified e-mail address
+	// from tweeting.
+	RequireVerifiedEmail bool
+
+	TOTPIssuer            string
+	TwoFactorTokenTTL     time.Duration
+	RequireAdminTwoFactor bool
+
+	// Failed logins from one IP address are limited to LoginIPMaxFailures per
+	// LoginIPWindow. An account is locked for LoginLockoutBase once it reaches
+	// LoginLockoutThreshold consecutive failures, and every further failure
+	// doubles the lock, up to LoginLockoutMax.
+	LoginIPMaxFailures    int64
+	LoginIPWindow         time.Duration
+	LoginLockoutThreshold int
+	LoginLockoutBase      time.Duration
+	LoginLockoutMax       time.Duration
+
+	// Deactivated accounts are purged once AccountDeletionGrace has passed.
+	// Local media of purged accounts is removed from MediaDir.
+	AccountDeletionGrace time.Duration
+	MediaDir             string
+
+	// A tweet can be edited TweetEditLimit times within TweetEditWindow of
+	// being posted.
+	TweetEditWindow time.Duration
+	TweetEditLimit  int
+	// TweetMaxLength is the weighted length tweets may have, counted like
+	// twitter-text does.
+	TweetMaxLength int
+
+	// Trending hashtags are ranked by their use over TrendWindow, where a use
+	// counts half as much every TrendHalfLife. They are recomputed every
+	// TrendInterval.
+	TrendWindow   time.Duration
+	TrendHalfLife time.Duration
+	TrendInterval time.Duration
+
+	// Scheduled tweets are published within ScheduleInterval of being due.
+	ScheduleInterval time.Duration
+
+	// DraftLimit is how many drafts a user may keep.
+	DraftLimit int
+}
+
+func Load() Config {
+	cfg := Config{
+		RedisURL:         os.Getenv("REDIS_URL"),
+		AccessTokenTTL:   getDuration("ACCESS_TOKEN_TTL", 15*time.Minute),
+		RefreshTokenTTL:  getDuration("REFRESH_TOKEN_TTL", 30*24*time.Hour),
+		PasswordResetTTL: getDuration("PASSWORD_RESET_TTL", time.Hour),
+		Mail: mailer.Config{
+			Driver:       os.Getenv("MAIL_DRIVER"),
+			From:         getString("MAIL_FROM", "no-reply@localhost"),
+			SMTPHost:     os.Getenv("SMTP_HOST"),
+			SMTPPort:     getString("SMTP_PORT", "587"),
+			SMTPUsername: os.Getenv("SMTP_USERNAME"),
+			SMTPPassword: os.Getenv("SMTP_PASSWORD"),
+			OutboxDir:    os.Getenv("MAIL_OUTBOX_DIR"),
+		},
+		JWT: auth.KeyConfig{
+			Algorithm: getString("JWT_ALGORITHM", auth.AlgEdDSA),
+			Rotation:  getDuration("JWT_KEY_ROTATION", 30*24*time.Hour),
+			Grace:     getDuration("JWT_KEY_GRACE", 24*time.Hour),
+			Issuer:    os.Getenv("JWT_ISSUER"),
+		},
+		Password: password.Config{
+			Memory:       uint32(getInt("ARGON2_MEMORY", 64*1024)),
+			Iterations:   uint32(getInt("ARGON2_ITERATIONS", 3)),
+			Parallelism:  uint8(getInt("ARGON2_PARALLELISM", 2)),
+			MinLength:    getInt("PASSWORD_MIN_LENGTH", 8),
+			RejectCommon: getBool("PASSWORD_REJECT_COMMON", true),
+		},
+		EmailVerificationTTL:  getDuration("EMAIL_VERIFICATION_TTL", 48*time.Hour),
+		RequireVerifiedEmail:  getBool("REQUIRE_VERIFIED_EMAIL", false),
+		TOTPIssuer:            getString("TOTP_ISSUER", "Twitter"),
+		TwoFactorTokenTTL:     getDuration("TWO_FACTOR_TOKEN_TTL", 5*time.Minute),
+		RequireAdminTwoFactor: getBool("REQUIRE_ADMIN_2FA", false),
+		LoginIPMaxFailures:    int64(getInt("LOGIN_IP_MAX_FAILURES", 20)),
+		LoginIPWindow:         getDuration("LOGIN_IP_WINDOW", 15*time.Minute),
+		LoginLockoutThreshold: getInt("LOGIN_LOCKOUT_THRESHOLD", 5),
+		LoginLockoutBase:      getDuration("LOGIN_LOCKOUT_BASE", time.Minute),
+		LoginLockoutMax:       getDuration("LOGIN_LOCKOUT_MAX", 24*time.Hour),
+		AccountDeletionGrace:  getDuration("ACCOUNT_DELETION_GRACE", 30*24*time.Hour),
+		MediaDir:              getString("MEDIA_DIR", "./public/images"),
+		TweetEditWindow:       getDuration("TWEET_EDIT_WINDOW", time.Hour),
+		TweetEditLimit:        getInt("TWEET_EDIT_LIMIT", 5),
+		TweetMaxLength:        getInt("TWEET_MAX_LENGTH", 280),
+		TrendWindow:           getDuration("TREND_WINDOW", 24*time.Hour),
+		TrendHalfLife:         getDuration("TREND_HALF_LIFE", 6*time.Hour),
+		TrendInterval:         getDuration("TREND_INTERVAL", 5*time.Minute),
+		ScheduleInterval:      getDuration("SCHEDULE_INTERVAL", 10*time.Second),
+		DraftLimit:            getInt("DRAFT_LIMIT", 100),
+	}
+
+	// A key must stay published for as long as the tokens it signed are valid.
+	if cfg.JWT.Grace < cfg.AccessTokenTTL {
+		cfg.JWT.Grace = cfg.AccessTokenTTL
+	}
+	return cfg
+}
+
+func getString(key string, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
+
+func getInt(key string, fallback int) int {
+	value, err := strconv.Atoi(os.Getenv(key))
+	if err != nil || value <= 0 {
+		return fallback
+	}
+	return value
+}
+
+func getBool(key string, fallback bool) bool {
+	value, err := strconv.ParseBool(os.Getenv(key))
+	if err != nil {
+		return fallback
+	}
+	return value
+}
+
+func getDuration(key string, fallback time.Duration) time.Duration {
+	value, err := time.ParseDuration(os.Getenv(key))
+	if err != nil || value <= 0 {
+		return fallback
+	}
+	return value
+}
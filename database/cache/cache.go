@@ -0,0 +1,39 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+)
+
+var ErrNotFound = errors.New("cache: key not found")
+
+// Cache is the small key/value surface the API needs from Redis. Keys expire
+// on their own, so callers never have to clean up after themselves.
+type Cache interface {
+	Get(ctx context.Context, key string) (string, error)
+	Set(ctx context.Context, key string, value string, ttl time.Duration) error
+	Delete(ctx context.Context, key string) error
+	// Incr increments the counter at key and returns the new value. A new
+	// counter expires after ttl; later increments do not extend it.
+	Incr(ctx context.Context, key string, ttl time.Duration) (int64, error)
+}
+
+// New connects to Redis when url is set and falls back to an in-process
+// cache otherwise. The fallback is only safe for a single API replica.
+func New(url string) Cache {
+	if url == "" {
+		log.Println("REDIS_URL is not set, using in-memory cache")
+		return NewMemory()
+	}
+
+	c, err := NewRedis(url)
+	if err != nil {
+		log.Printf("Failed to connect to redis, using in-memory cache: %v", err)
+		return NewMemory()
+	}
+
+	log.Println("Redis connection established")
+	return c
+}
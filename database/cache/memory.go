@@ -0,0 +1,95 @@
+package cache
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+)
+
+type memoryItem struct {
+	value     string
+	expiresAt time.Time
+}
+
+func (i memoryItem) expired(now time.Time) bool {
+	return !i.expiresAt.IsZero() && now.After(i.expiresAt)
+}
+
+type Memory struct {
+	mu    sync.Mutex
+	items map[string]memoryItem
+}
+
+func NewMemory() *Memory {
+	m := &Memory{items: make(map[string]memoryItem)}
+	go m.sweep(time.Minute)
+	return m
+}
+
+func (m *Memory) Get(_ context.Context, key string) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	item, ok := m.items[key]
+	if !ok || item.expired(time.Now()) {
+		return "", ErrNotFound
+	}
+	return item.value, nil
+}
+
+func (m *Memory) Set(_ context.Context, key string, value string, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	item := memoryItem{value: value}
+	if ttl > 0 {
+		item.expiresAt = time.Now().Add(ttl)
+	}
+	m.items[key] = item
+	return nil
+}
+
+func (m *Memory) Delete(_ context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.items, key)
+	return nil
+}
+
+func (m *Memory) Incr(_ context.Context, key string, ttl time.Duration) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	item, ok := m.items[key]
+	if !ok || item.expired(now) {
+		item = memoryItem{value: "0"}
+		if ttl > 0 {
+			item.expiresAt = now.Add(ttl)
+		}
+	}
+
+	value, err := strconv.ParseInt(item.value, 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	value++
+	item.value = strconv.FormatInt(value, 10)
+	m.items[key] = item
+	return value, nil
+}
+
+func (m *Memory) sweep(interval time.Duration) {
+	for range time.Tick(interval) {
+		now := time.Now()
+		m.mu.Lock()
+		for key, item := range m.items {
+			if item.expired(now) {
+				delete(m.items, key)
+			}
+		}
+		m.mu.Unlock()
+	}
+}
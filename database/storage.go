@@ -10,23 +10,65 @@ type IStore interface {
 	Tweet() storage.Tweet
 	Like() storage.Like
 	Follow() storage.Follow
+	RefreshToken() storage.RefreshToken
+	Role() storage.Role
+	UserToken() storage.UserToken
+	TwoFactor() storage.TwoFactor
+	Lockout() storage.Lockout
+	PersonalToken() storage.PersonalToken
+	SigningKey() storage.SigningKey
+	Session() storage.Session
+	Block() storage.Block
+	Mute() storage.Mute
+	Hashtag() storage.Hashtag
+	ScheduledTweet() storage.ScheduledTweet
+	Draft() storage.Draft
+	Poll() storage.Poll
 }
 
 type Store struct {
-	db     *gorm.DB
-	user   storage.User
-	tweet  storage.Tweet
-	like   storage.Like
-	follow storage.Follow
+	db             *gorm.DB
+	user           storage.User
+	tweet          storage.Tweet
+	like           storage.Like
+	follow         storage.Follow
+	refreshToken   storage.RefreshToken
+	role           storage.Role
+	userToken      storage.UserToken
+	twoFactor      storage.TwoFactor
+	lockout        storage.Lockout
+	personalToken  storage.PersonalToken
+	signingKey     storage.SigningKey
+	session        storage.Session
+	block          storage.Block
+	mute           storage.Mute
+	hashtag        storage.Hashtag
+	scheduledTweet storage.ScheduledTweet
+	draft          storage.Draft
+	poll           storage.Poll
 }
 
 func New(db *gorm.DB) *Store {
 	return &Store{
-		db:     db,
-		user:   storage.NewUserRepo(db),
-		tweet:  storage.NewTweetRepo(db),
-		like:   storage.NewLikeRepo(db),
-		follow: storage.NewFollowRepo(db),
+		db:             db,
+		user:           storage.NewUserRepo(db),
+		tweet:          storage.NewTweetRepo(db),
+		like:           storage.NewLikeRepo(db),
+		follow:         storage.NewFollowRepo(db),
+		refreshToken:   storage.NewRefreshTokenRepo(db),
+		role:           storage.NewRoleRepo(db),
+		userToken:      storage.NewUserTokenRepo(db),
+		twoFactor:      storage.NewTwoFactorRepo(db),
+		lockout:        storage.NewLockoutRepo(db),
+		personalToken:  storage.NewPersonalTokenRepo(db),
+		signingKey:     storage.NewSigningKeyRepo(db),
+		session:        storage.NewSessionRepo(db),
+		block:          storage.NewBlockRepo(db),
+		mute:           storage.NewMuteRepo(db),
+		hashtag:        storage.NewHashtagRepo(db),
+		scheduledTweet: storage.NewScheduledTweetRepo(db),
+		draft:          storage.NewDraftRepo(db),
+		poll:           storage.NewPollRepo(db),
 	}
 }
 
@@ -37,3 +79,31 @@ func (s *Store) Tweet() storage.Tweet { return s.tweet }
 func (s *Store) Like() storage.Like { return s.like }
 
 func (s *Store) Follow() storage.Follow { return s.follow }
+
+func (s *Store) RefreshToken() storage.RefreshToken { return s.refreshToken }
+
+func (s *Store) Role() storage.Role { return s.role }
+
+func (s *Store) UserToken() storage.UserToken { return s.userToken }
+
+func (s *Store) TwoFactor() storage.TwoFactor { return s.twoFactor }
+
+func (s *Store) Lockout() storage.Lockout { return s.lockout }
+
+func (s *Store) PersonalToken() storage.PersonalToken { return s.personalToken }
+
+func (s *Store) SigningKey() storage.SigningKey { return s.signingKey }
+
+func (s *Store) Session() storage.Session { return s.session }
+
+func (s *Store) Block() storage.Block { return s.block }
+
+func (s *Store) Mute() storage.Mute { return s.mute }
+
+func (s *Store) Hashtag() storage.Hashtag { return s.hashtag }
+
+func (s *Store) ScheduledTweet() storage.ScheduledTweet { return s.scheduledTweet }
+
+func (s *Store) Draft() storage.Draft { return s.draft }
+
+func (s *Store) Poll() storage.Poll { return s.poll }
@@ -0,0 +1,77 @@
+package storage
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"project/models"
+)
+
+type BlockRepo struct {
+	db *gorm.DB
+}
+
+func NewBlockRepo(db *gorm.DB) Block {
+	return &BlockRepo{db: db}
+}
+
+// Create stores the block and removes any follow between the two users.
+// Blocking someone who is already blocked is not an error.
+func (r *BlockRepo) Create(block *models.Block) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Where("(follower_id = ? AND followed_id = ?) OR (follower_id = ? AND followed_id = ?)",
+			block.BlockerID, block.BlockedID, block.BlockedID, block.BlockerID).
+			Delete(&models.Follow{}).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Clauses(clause.OnConflict{DoNothing: true}).Create(block).Error
+	})
+}
+
+func (r *BlockRepo) Delete(blockerID, blockedID uuid.UUID) error {
+	return r.db.Where("blocker_id = ? AND blocked_id = ?", blockerID, blockedID).Delete(&models.Block{}).Error
+}
+
+func (r *BlockRepo) IsBlocking(blockerID, blockedID uuid.UUID) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.Block{}).
+		Where("blocker_id = ? AND blocked_id = ?", blockerID, blockedID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// EitherBlocked reports whether one of the two users blocked the other.
+func (r *BlockRepo) EitherBlocked(a, b uuid.UUID) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.Block{}).
+		Where("(blocker_id = ? AND blocked_id = ?) OR (blocker_id = ? AND blocked_id = ?)", a, b, b, a).
+		Count(&count).Error
+	return count > 0, err
+}
+
+type MuteRepo struct {
+	db *gorm.DB
+}
+
+func NewMuteRepo(db *gorm.DB) Mute {
+	return &MuteRepo{db: db}
+}
+
+// Create stores the mute. Muting someone who is already muted is not an error.
+func (r *MuteRepo) Create(mute *models.Mute) error {
+	return r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(mute).Error
+}
+
+func (r *MuteRepo) Delete(muterID, mutedID uuid.UUID) error {
+	return r.db.Where("muter_id = ? AND muted_id = ?", muterID, mutedID).Delete(&models.Mute{}).Error
+}
+
+func (r *MuteRepo) IsMuting(muterID, mutedID uuid.UUID) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.Mute{}).
+		Where("muter_id = ? AND muted_id = ?", muterID, mutedID).
+		Count(&count).Error
+	return count > 0, err
+}
@@ -0,0 +1,128 @@
+package storage
+
+import (
+	"errors"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"project/models"
+)
+
+var ErrDraftLimit = errors.New("draft limit reached")
+
+type DraftRepo struct {
+	db *gorm.DB
+}
+
+func NewDraftRepo(db *gorm.DB) Draft {
+	return &DraftRepo{db: db}
+}
+
+// Create saves a draft unless its author already has limit drafts, in which
+// case it fails with ErrDraftLimit. The author is locked while the drafts
+// are counted, so concurrent requests cannot exceed the limit.
+func (r *DraftRepo) Create(draft *models.Draft, limit int) (string, error) {
+	draft.Id = uuid.New()
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var user models.User
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id").
+			Where("id = ?", draft.UserID).
+			First(&user).Error
+		if err != nil {
+			return err
+		}
+
+		var count int64
+		if err := tx.Model(&models.Draft{}).Where("user_id = ?", draft.UserID).Count(&count).Error; err != nil {
+			return err
+		}
+		if count >= int64(limit) {
+			return ErrDraftLimit
+		}
+
+		return tx.Create(draft).Error
+	})
+	if err != nil {
+		return "", err
+	}
+	return draft.Id.String(), nil
+}
+
+func (r *DraftRepo) Get(id, userID uuid.UUID) (*models.Draft, error) {
+	var draft models.Draft
+	if err := r.db.Where("id = ? AND user_id = ?", id, userID).First(&draft).Error; err != nil {
+		return nil, err
+	}
+	return &draft, nil
+}
+
+// GetAllForUser lists the user's drafts, the most recently saved first.
+func (r *DraftRepo) GetAllForUser(req models.GetDraftsRequest) (*models.GetDraftsResponse, error) {
+	var (
+		resp   models.GetDraftsResponse
+		query  = r.db.Model(&models.Draft{}).Where("user_id = ?", req.UserID)
+		offset = (req.Page - 1) * req.Limit
+	)
+
+	if err := query.Session(&gorm.Session{}).Count(&resp.Count).Error; err != nil {
+		return nil, err
+	}
+
+	err := query.Order("updated_at DESC, id").Offset(int(offset)).Limit(int(req.Limit)).Find(&resp.Drafts).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// Update saves the draft over the one of its author with the same id. It
+// returns gorm.ErrRecordNotFound when there is no such draft.
+func (r *DraftRepo) Update(draft *models.Draft) error {
+	result := r.db.Model(&models.Draft{}).
+		Where("id = ? AND user_id = ?", draft.Id, draft.UserID).
+		Select("content", "image_path", "video_path", "in_reply_to_id", "quoted_tweet_id", "poll", "updated_at").
+		Updates(draft)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// Delete deletes a draft of the user. It returns gorm.ErrRecordNotFound
+// when there is no such draft.
+func (r *DraftRepo) Delete(id, userID uuid.UUID) error {
+	result := r.db.Where("id = ? AND user_id = ?", id, userID).Delete(&models.Draft{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// Publish creates the tweet and deletes the draft it was made from in one
+// transaction, so a draft is published at most once. It returns
+// gorm.ErrRecordNotFound when the user has no such draft.
+func (r *DraftRepo) Publish(id, userID uuid.UUID, tweet *models.Tweet) (string, error) {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("id = ? AND user_id = ?", id, userID).Delete(&models.Draft{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		return createTweet(tx, tweet)
+	})
+	if err != nil {
+		return "", err
+	}
+	return tweet.Id.String(), nil
+}
@@ -2,7 +2,9 @@ package storage
 
 import (
 	"github.com/google/uuid"
+	"project/etc/tweettext"
 	"project/models"
+	"time"
 )
 
 type User interface {
@@ -11,7 +13,21 @@ type User interface {
 	Delete(req models.RequestId) error
 	Get(req models.RequestId) (*models.User, error)
 	GetAll(req models.GetAllUsersRequest) (*models.GetAllUsersResponse, error)
+	GetByIDs(ids []uuid.UUID) ([]models.User, error)
 	GetByUsername(username string) (*models.User, error)
+	ResolveMentions(entities *tweettext.Entities) error
+	GetByEmail(email string) (*models.User, error)
+	GetByLogin(login string) (*models.User, error)
+	UpdatePassword(id uuid.UUID, passwordHash string) error
+	ReplacePasswordHash(id uuid.UUID, oldHash, newHash string) error
+	MarkEmailVerified(id uuid.UUID) error
+	RegisterFailedLogin(id uuid.UUID) (int, error)
+	LockUntil(id uuid.UUID, until time.Time) error
+	ResetFailedLogins(id uuid.UUID) error
+	Deactivate(id uuid.UUID, by *uuid.UUID) error
+	Reactivate(id uuid.UUID) error
+	GetDeactivatedBefore(cutoff time.Time, limit int) ([]uuid.UUID, error)
+	Purge(id uuid.UUID) ([]string, error)
 }
 
 type Tweet interface {
@@ -21,11 +37,20 @@ type Tweet interface {
 	Get(req models.RequestId) (*models.Tweet, error)
 	GetAll(req models.GetAllTweetsRequest) (*models.GetAllTweetsResponse, error)
 	GetTweetsForUser(Id models.RequestId, req models.GetAllTweetsRequest) (*models.GetAllTweetsResponse, error)
+	GetRevisions(tweetID uuid.UUID) ([]models.TweetRevision, error)
+	Retweet(userID, tweetID uuid.UUID) (string, error)
+	Unretweet(userID, tweetID uuid.UUID) error
+	RetweetedIDs(userID uuid.UUID, tweetIDs []uuid.UUID) ([]uuid.UUID, error)
+	GetConversation(conversationID uuid.UUID, limit int) ([]models.Tweet, error)
+	VisibleIDs(viewerID uuid.UUID, tweetIDs []uuid.UUID) ([]uuid.UUID, error)
+	GetVisible(viewerID uuid.UUID, tweetIDs []uuid.UUID) ([]models.Tweet, error)
 }
 
 type Like interface {
 	Create(like *models.Like) error
 	Delete(userID, tweetID uuid.UUID) error
+	LikedTweetIDs(userID uuid.UUID, tweetIDs []uuid.UUID) ([]uuid.UUID, error)
+	CountByTweet(tweetIDs []uuid.UUID) (map[uuid.UUID]int64, error)
 }
 
 type Follow interface {
@@ -33,3 +58,102 @@ type Follow interface {
 	Delete(followerID, followedID uuid.UUID) error
 	IsFollowing(followerID, followedID uuid.UUID) (bool, error)
 }
+
+type RefreshToken interface {
+	Create(token *models.RefreshToken) error
+	GetByHash(hash string) (*models.RefreshToken, error)
+	Rotate(old *models.RefreshToken, next *models.RefreshToken) error
+}
+
+type Role interface {
+	Create(role *models.Role, permissions []string) (string, error)
+	GetAll() ([]models.Role, error)
+	GetByName(name string) (*models.Role, error)
+	GetForUser(userID uuid.UUID) ([]models.Role, error)
+	Assign(userID, roleID uuid.UUID) error
+	Revoke(userID, roleID uuid.UUID) error
+	PermissionsForUser(userID uuid.UUID) ([]string, error)
+}
+
+type UserToken interface {
+	Create(token *models.UserToken) error
+	Consume(purpose, hash string) (*models.UserToken, error)
+}
+
+type TwoFactor interface {
+	SetPendingSecret(userID uuid.UUID, secret string) error
+	Enable(userID uuid.UUID, step int64, codeHashes []string) error
+	Disable(userID uuid.UUID) error
+	UseStep(userID uuid.UUID, step int64) (bool, error)
+	ConsumeRecoveryCode(userID uuid.UUID, codeHash string) (bool, error)
+	SetRequired(userID uuid.UUID, required bool) error
+}
+
+type Lockout interface {
+	Create(lockout *models.LoginLockout) error
+	GetAll(req models.GetAllLockoutsRequest) (*models.GetAllLockoutsResponse, error)
+}
+
+type PersonalToken interface {
+	Create(token *models.PersonalAccessToken) (string, error)
+	GetActiveByHash(hash string) (*models.PersonalAccessToken, error)
+	GetAllForUser(userID uuid.UUID) ([]models.PersonalAccessToken, error)
+	Revoke(id, userID uuid.UUID) error
+	TouchLastUsed(id uuid.UUID, at time.Time) error
+}
+
+type SigningKey interface {
+	GetAllVerifiable(now time.Time) ([]models.SigningKey, error)
+	Append(key *models.SigningKey, latestKid string) (bool, error)
+}
+
+type Session interface {
+	Create(session *models.Session, event *models.LoginEvent) error
+	Get(id uuid.UUID) (*models.Session, error)
+	GetAllForUser(userID uuid.UUID, activeSince time.Time) ([]models.Session, error)
+	Seen(id uuid.UUID, at time.Time) error
+	Revoke(id, userID uuid.UUID) error
+	RevokeAllForUser(userID uuid.UUID) error
+	GetLoginHistory(req models.GetLoginHistoryRequest) (*models.GetLoginHistoryResponse, error)
+}
+
+type Block interface {
+	Create(block *models.Block) error
+	Delete(blockerID, blockedID uuid.UUID) error
+	IsBlocking(blockerID, blockedID uuid.UUID) (bool, error)
+	EitherBlocked(a, b uuid.UUID) (bool, error)
+}
+
+type Mute interface {
+	Create(mute *models.Mute) error
+	Delete(muterID, mutedID uuid.UUID) error
+	IsMuting(muterID, mutedID uuid.UUID) (bool, error)
+}
+
+type Hashtag interface {
+	ComputeTrends(now time.Time, window, halfLife time.Duration, limit int) error
+	GetTrends(limit int) ([]models.Trend, error)
+}
+
+type ScheduledTweet interface {
+	Create(tweet *models.ScheduledTweet) (string, error)
+	GetAllForUser(req models.GetScheduledTweetsRequest) (*models.GetScheduledTweetsResponse, error)
+	Reschedule(id, userID uuid.UUID, publishAt time.Time) error
+	Cancel(id, userID uuid.UUID) error
+	PublishDue(now time.Time, limit int) (int, error)
+}
+
+type Draft interface {
+	Create(draft *models.Draft, limit int) (string, error)
+	Get(id, userID uuid.UUID) (*models.Draft, error)
+	GetAllForUser(req models.GetDraftsRequest) (*models.GetDraftsResponse, error)
+	Update(draft *models.Draft) error
+	Delete(id, userID uuid.UUID) error
+	Publish(id, userID uuid.UUID, tweet *models.Tweet) (string, error)
+}
+
+type Poll interface {
+	GetByTweetIDs(tweetIDs []uuid.UUID) ([]models.Poll, error)
+	VotedOptions(userID uuid.UUID, pollIDs []uuid.UUID) (map[uuid.UUID]uuid.UUID, error)
+	Vote(tweetID, userID, optionID uuid.UUID, now time.Time) error
+}
@@ -0,0 +1,108 @@
+package storage
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"project/etc/tweettext"
+	"project/models"
+	"time"
+)
+
+// trendsLock is the advisory lock taken while trends are recomputed, so
+// several instances of the API do not replace each other's results.
+const trendsLock = 7291002
+
+type HashtagRepo struct {
+	db *gorm.DB
+}
+
+func NewHashtagRepo(db *gorm.DB) Hashtag {
+	return &HashtagRepo{db: db}
+}
+
+// linkHashtags replaces the hashtags linked to the tweet with the ones in
+// its entities. It runs inside the transaction that writes the tweet.
+func linkHashtags(tx *gorm.DB, tweet *models.Tweet) error {
+	if err := tx.Where("tweet_id = ?", tweet.Id).Delete(&models.TweetHashtag{}).Error; err != nil {
+		return err
+	}
+	if tweet.Entities == nil || len(tweet.Entities.Hashtags) == 0 {
+		return nil
+	}
+
+	seen := make(map[string]bool)
+	var hashtags []models.Hashtag
+	for _, entity := range tweet.Entities.Hashtags {
+		tag := tweettext.NormalizeHashtag(entity.Tag)
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		hashtags = append(hashtags, models.Hashtag{Id: uuid.New(), Tag: tag})
+	}
+	if len(hashtags) == 0 {
+		return nil
+	}
+
+	err := tx.Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "tag"}}, DoNothing: true}).
+		Create(&hashtags).Error
+	if err != nil {
+		return err
+	}
+
+	var ids []uuid.UUID
+	if err := tx.Model(&models.Hashtag{}).Where("tag IN ?", keys(seen)).Pluck("id", &ids).Error; err != nil {
+		return err
+	}
+
+	links := make([]models.TweetHashtag, len(ids))
+	for i, id := range ids {
+		links[i] = models.TweetHashtag{TweetID: tweet.Id, HashtagID: id, CreatedAt: tweet.CreatedAt}
+	}
+	return tx.Create(&links).Error
+}
+
+// ComputeTrends replaces the trends with the limit hashtags that scored
+// highest over the window before now.
+func (r *HashtagRepo) ComputeTrends(now time.Time, window, halfLife time.Duration, limit int) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", trendsLock).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("1 = 1").Delete(&models.Trend{}).Error; err != nil {
+			return err
+		}
+
+		return tx.Exec(`INSERT INTO trends (hashtag_id, tag, rank, score, tweet_count, computed_at)
+			SELECT id, tag, ROW_NUMBER() OVER (ORDER BY score DESC, tweet_count DESC, tag), score, tweet_count, ?
+			FROM (
+				SELECT h.id, h.tag,
+					SUM(POWER(0.5, EXTRACT(EPOCH FROM (?::timestamptz - th.created_at)) / ?)) AS score,
+					COUNT(*) AS tweet_count
+				FROM tweet_hashtags th
+				JOIN hashtags h ON h.id = th.hashtag_id
+				JOIN tweets t ON t.id = th.tweet_id AND t.deleted_at IS NULL
+				WHERE th.created_at > ? AND t.user_id IN (?)
+				GROUP BY h.id, h.tag
+				ORDER BY score DESC, tweet_count DESC, h.tag
+				LIMIT ?
+			) AS scored`,
+			now, now, halfLife.Seconds(), now.Add(-window), activeUsers(r.db), limit).Error
+	})
+}
+
+// GetTrends returns the top limit trends of the latest computation.
+func (r *HashtagRepo) GetTrends(limit int) ([]models.Trend, error) {
+	var trends []models.Trend
+	err := r.db.Order("rank").Limit(limit).Find(&trends).Error
+	return trends, err
+}
+
+func keys(set map[string]bool) []string {
+	list := make([]string, 0, len(set))
+	for key := range set {
+		list = append(list, key)
+	}
+	return list
+}
@@ -23,3 +23,35 @@ func (r *LikeRepo) Create(like *models.Like) error {
 func (r *LikeRepo) Delete(userID, tweetID uuid.UUID) error {
 	return r.db.Where("user_id = ? AND tweet_id = ?", userID, tweetID).Delete(&models.Like{}).Error
 }
+
+// LikedTweetIDs returns which of tweetIDs the user has liked.
+func (r *LikeRepo) LikedTweetIDs(userID uuid.UUID, tweetIDs []uuid.UUID) ([]uuid.UUID, error) {
+	var liked []uuid.UUID
+	err := r.db.Model(&models.Like{}).
+		Where("user_id = ? AND tweet_id IN ?", userID, tweetIDs).
+		Pluck("tweet_id", &liked).Error
+	return liked, err
+}
+
+// CountByTweet returns the number of likes of each of tweetIDs. Tweets
+// without likes are left out.
+func (r *LikeRepo) CountByTweet(tweetIDs []uuid.UUID) (map[uuid.UUID]int64, error) {
+	var rows []struct {
+		TweetID uuid.UUID
+		Count   int64
+	}
+	err := r.db.Model(&models.Like{}).
+		Select("tweet_id, COUNT(*) AS count").
+		Where("tweet_id IN ?", tweetIDs).
+		Group("tweet_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[uuid.UUID]int64, len(rows))
+	for _, row := range rows {
+		counts[row.TweetID] = row.Count
+	}
+	return counts, nil
+}
@@ -0,0 +1,43 @@
+package storage
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"project/models"
+)
+
+type LockoutRepo struct {
+	db *gorm.DB
+}
+
+func NewLockoutRepo(db *gorm.DB) Lockout {
+	return &LockoutRepo{db: db}
+}
+
+func (r *LockoutRepo) Create(lockout *models.LoginLockout) error {
+	lockout.Id = uuid.New()
+	return r.db.Create(lockout).Error
+}
+
+func (r *LockoutRepo) GetAll(req models.GetAllLockoutsRequest) (*models.GetAllLockoutsResponse, error) {
+	var (
+		resp   models.GetAllLockoutsResponse
+		query  = r.db.Model(&models.LoginLockout{})
+		offset = (req.Page - 1) * req.Limit
+	)
+
+	if req.UserID != uuid.Nil {
+		query = query.Where("user_id = ?", req.UserID)
+	}
+
+	if err := query.Count(&resp.Count).Error; err != nil {
+		return nil, err
+	}
+
+	err := query.Order("created_at DESC").Offset(int(offset)).Limit(int(req.Limit)).Find(&resp.Lockouts).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
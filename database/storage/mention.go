@@ -0,0 +1,78 @@
+package storage
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"project/etc/tweettext"
+	"project/models"
+	"strings"
+)
+
+// resolveMentions fills in the user id of every mention naming an active
+// account and drops the other mentions, which stay plain text. Usernames
+// match case-insensitively; an exact match wins over one differing in case.
+func resolveMentions(db *gorm.DB, entities *tweettext.Entities) error {
+	if len(entities.Mentions) == 0 {
+		return nil
+	}
+
+	names := make([]string, 0, len(entities.Mentions))
+	for _, mention := range entities.Mentions {
+		names = append(names, strings.ToLower(mention.Username))
+	}
+
+	var users []models.User
+	err := db.Select("id", "username").
+		Where("LOWER(username) IN ?", names).
+		Where("id IN (?)", activeUsers(db)).
+		Find(&users).Error
+	if err != nil {
+		return err
+	}
+
+	mentions := entities.Mentions[:0]
+	for _, mention := range entities.Mentions {
+		mention.UserID = ""
+		for _, user := range users {
+			if user.Username == mention.Username {
+				mention.UserID = user.Id.String()
+				break
+			}
+			if mention.UserID == "" && strings.EqualFold(user.Username, mention.Username) {
+				mention.UserID = user.Id.String()
+			}
+		}
+		if mention.UserID != "" {
+			mentions = append(mentions, mention)
+		}
+	}
+	entities.Mentions = mentions
+	return nil
+}
+
+// linkMentions replaces the users linked to the tweet as mentioned with the
+// resolved mentions in its entities. It runs inside the transaction that
+// writes the tweet.
+func linkMentions(tx *gorm.DB, tweet *models.Tweet) error {
+	if err := tx.Where("tweet_id = ?", tweet.Id).Delete(&models.Mention{}).Error; err != nil {
+		return err
+	}
+	if tweet.Entities == nil {
+		return nil
+	}
+
+	seen := make(map[uuid.UUID]bool)
+	var mentions []models.Mention
+	for _, entity := range tweet.Entities.Mentions {
+		userID, err := uuid.Parse(entity.UserID)
+		if err != nil || seen[userID] {
+			continue
+		}
+		seen[userID] = true
+		mentions = append(mentions, models.Mention{TweetID: tweet.Id, UserID: userID, CreatedAt: tweet.CreatedAt})
+	}
+	if len(mentions) == 0 {
+		return nil
+	}
+	return tx.Create(&mentions).Error
+}
@@ -0,0 +1,68 @@
+package storage
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"project/models"
+	"time"
+)
+
+type PersonalTokenRepo struct {
+	db *gorm.DB
+}
+
+func NewPersonalTokenRepo(db *gorm.DB) PersonalToken {
+	return &PersonalTokenRepo{db: db}
+}
+
+func (r *PersonalTokenRepo) Create(token *models.PersonalAccessToken) (string, error) {
+	token.Id = uuid.New()
+	if err := r.db.Create(token).Error; err != nil {
+		return "", err
+	}
+	return token.Id.String(), nil
+}
+
+// GetActiveByHash returns the token only while it is usable: not revoked,
+// not expired and owned by an account that still exists and is active.
+func (r *PersonalTokenRepo) GetActiveByHash(hash string) (*models.PersonalAccessToken, error) {
+	var token models.PersonalAccessToken
+	err := r.db.
+		Joins("JOIN users ON users.id = personal_access_tokens.user_id AND users.deleted_at IS NULL AND users.deactivated_at IS NULL").
+		Where("personal_access_tokens.token_hash = ?", hash).
+		Where("personal_access_tokens.revoked_at IS NULL").
+		Where("personal_access_tokens.expires_at IS NULL OR personal_access_tokens.expires_at > ?", time.Now()).
+		First(&token).Error
+	if err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+func (r *PersonalTokenRepo) GetAllForUser(userID uuid.UUID) ([]models.PersonalAccessToken, error) {
+	var tokens []models.PersonalAccessToken
+	err := r.db.Where("user_id = ?", userID).Order("created_at DESC").Find(&tokens).Error
+	if err != nil {
+		return nil, err
+	}
+	return tokens, nil
+}
+
+// Revoke revokes the token if it belongs to userID. It returns
+// gorm.ErrRecordNotFound when there is no such active token.
+func (r *PersonalTokenRepo) Revoke(id, userID uuid.UUID) error {
+	res := r.db.Model(&models.PersonalAccessToken{}).
+		Where("id = ? AND user_id = ? AND revoked_at IS NULL", id, userID).
+		Update("revoked_at", time.Now())
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *PersonalTokenRepo) TouchLastUsed(id uuid.UUID, at time.Time) error {
+	return r.db.Model(&models.PersonalAccessToken{}).Where("id = ?", id).Update("last_used_at", at).Error
+}
@@ -0,0 +1,117 @@
+package storage
+
+import (
+	"errors"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"project/models"
+	"time"
+)
+
+var (
+	ErrPollClosed   = errors.New("poll is closed")
+	ErrAlreadyVoted = errors.New("already voted in this poll")
+	ErrPollOption   = errors.New("option does not belong to the poll")
+)
+
+type PollRepo struct {
+	db *gorm.DB
+}
+
+func NewPollRepo(db *gorm.DB) Poll {
+	return &PollRepo{db: db}
+}
+
+// createPoll attaches a poll to a tweet being created. The poll runs from
+// now, so a scheduled tweet's poll starts when it is published.
+func createPoll(tx *gorm.DB, tweetID uuid.UUID, req models.CreatePoll) error {
+	poll := models.Poll{
+		Id:      uuid.New(),
+		TweetID: tweetID,
+		EndsAt:  time.Now().Add(time.Duration(req.DurationMinutes) * time.Minute),
+	}
+	for i, label := range req.Options {
+		poll.Options = append(poll.Options, models.PollOption{
+			Id:       uuid.New(),
+			PollID:   poll.Id,
+			Position: i + 1,
+			Label:    label,
+		})
+	}
+	return tx.Create(&poll).Error
+}
+
+// GetByTweetIDs returns the polls of those of tweetIDs that have one, with
+// their options in order.
+func (r *PollRepo) GetByTweetIDs(tweetIDs []uuid.UUID) ([]models.Poll, error) {
+	var polls []models.Poll
+	err := r.db.Preload("Options", func(db *gorm.DB) *gorm.DB {
+		return db.Order("position")
+	}).Where("tweet_id IN ?", tweetIDs).Find(&polls).Error
+	return polls, err
+}
+
+// VotedOptions returns the option the user voted for in each of pollIDs.
+// Polls the user has not voted in are left out.
+func (r *PollRepo) VotedOptions(userID uuid.UUID, pollIDs []uuid.UUID) (map[uuid.UUID]uuid.UUID, error) {
+	var votes []models.PollVote
+	err := r.db.Where("user_id = ? AND poll_id IN ?", userID, pollIDs).Find(&votes).Error
+	if err != nil {
+		return nil, err
+	}
+
+	voted := make(map[uuid.UUID]uuid.UUID, len(votes))
+	for _, vote := range votes {
+		voted[vote.PollID] = vote.OptionID
+	}
+	return voted, nil
+}
+
+// Vote records the user's vote for an option of the tweet's poll and counts
+// it. It returns gorm.ErrRecordNotFound when the tweet has no poll,
+// ErrPollOption when the option is not one of the poll's, ErrPollClosed
+// once the poll has ended and ErrAlreadyVoted for a second vote.
+func (r *PollRepo) Vote(tweetID, userID, optionID uuid.UUID, now time.Time) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var poll models.Poll
+		if err := tx.Where("tweet_id = ?", tweetID).First(&poll).Error; err != nil {
+			return err
+		}
+		if !now.Before(poll.EndsAt) {
+			return ErrPollClosed
+		}
+
+		var option models.PollOption
+		err := tx.Where("id = ? AND poll_id = ?", optionID, poll.Id).First(&option).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrPollOption
+		}
+		if err != nil {
+			return err
+		}
+
+		vote := models.PollVote{
+			Id:       uuid.New(),
+			PollID:   poll.Id,
+			UserID:   userID,
+			OptionID: option.Id,
+		}
+		result := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "poll_id"}, {Name: "user_id"}},
+			DoNothing: true,
+		}).Create(&vote)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrAlreadyVoted
+		}
+
+		err = tx.Model(&option).Update("vote_count", gorm.Expr("vote_count + 1")).Error
+		if err != nil {
+			return err
+		}
+		return tx.Model(&poll).Update("vote_count", gorm.Expr("vote_count + 1")).Error
+	})
+}
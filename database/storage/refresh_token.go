@@ -0,0 +1,55 @@
+package storage
+
+import (
+	"errors"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"project/models"
+	"time"
+)
+
+var ErrRefreshTokenReused = errors.New("refresh token has already been used")
+
+type RefreshTokenRepo struct {
+	db *gorm.DB
+}
+
+func NewRefreshTokenRepo(db *gorm.DB) RefreshToken {
+	return &RefreshTokenRepo{db: db}
+}
+
+func (r *RefreshTokenRepo) Create(token *models.RefreshToken) error {
+	token.Id = uuid.New()
+	return r.db.Create(token).Error
+}
+
+func (r *RefreshTokenRepo) GetByHash(hash string) (*models.RefreshToken, error) {
+	var token models.RefreshToken
+	if err := r.db.Where("token_hash = ?", hash).First(&token).Error; err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// Rotate revokes old and stores next in its place. The revocation only
+// succeeds while old is still active, so two concurrent refreshes with the
+// same token cannot both win: the loser gets ErrRefreshTokenReused.
+func (r *RefreshTokenRepo) Rotate(old *models.RefreshToken, next *models.RefreshToken) error {
+	next.Id = uuid.New()
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&models.RefreshToken{}).
+			Where("id = ? AND revoked_at IS NULL", old.Id).
+			Updates(map[string]interface{}{
+				"revoked_at":     time.Now(),
+				"replaced_by_id": next.Id,
+			})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return ErrRefreshTokenReused
+		}
+
+		return tx.Create(next).Error
+	})
+}
@@ -0,0 +1,90 @@
+package storage
+
+import (
+	"errors"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"project/models"
+)
+
+var ErrUnknownPermission = errors.New("unknown permission")
+
+type RoleRepo struct {
+	db *gorm.DB
+}
+
+func NewRoleRepo(db *gorm.DB) Role {
+	return &RoleRepo{db: db}
+}
+
+func (r *RoleRepo) Create(role *models.Role, permissions []string) (string, error) {
+	role.Id = uuid.New()
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("name IN ?", permissions).Find(&role.Permissions).Error; err != nil {
+			return err
+		}
+		if len(role.Permissions) != len(permissions) {
+			return ErrUnknownPermission
+		}
+
+		return tx.Create(role).Error
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return role.Id.String(), nil
+}
+
+func (r *RoleRepo) GetAll() ([]models.Role, error) {
+	var roles []models.Role
+	if err := r.db.Preload("Permissions").Order("name").Find(&roles).Error; err != nil {
+		return nil, err
+	}
+	return roles, nil
+}
+
+func (r *RoleRepo) GetByName(name string) (*models.Role, error) {
+	var role models.Role
+	if err := r.db.Where("name = ?", name).First(&role).Error; err != nil {
+		return nil, err
+	}
+	return &role, nil
+}
+
+func (r *RoleRepo) GetForUser(userID uuid.UUID) ([]models.Role, error) {
+	var roles []models.Role
+	err := r.db.Preload("Permissions").
+		Joins("JOIN user_roles ON user_roles.role_id = roles.id").
+		Where("user_roles.user_id = ?", userID).
+		Order("roles.name").
+		Find(&roles).Error
+	if err != nil {
+		return nil, err
+	}
+	return roles, nil
+}
+
+func (r *RoleRepo) Assign(userID, roleID uuid.UUID) error {
+	return r.db.Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&models.UserRole{UserID: userID, RoleID: roleID}).Error
+}
+
+func (r *RoleRepo) Revoke(userID, roleID uuid.UUID) error {
+	return r.db.Where("user_id = ? AND role_id = ?", userID, roleID).Delete(&models.UserRole{}).Error
+}
+
+func (r *RoleRepo) PermissionsForUser(userID uuid.UUID) ([]string, error) {
+	var permissions []string
+	err := r.db.Model(&models.Permission{}).
+		Distinct("permissions.name").
+		Joins("JOIN role_permissions ON role_permissions.permission_id = permissions.id").
+		Joins("JOIN user_roles ON user_roles.role_id = role_permissions.role_id").
+		Where("user_roles.user_id = ?", userID).
+		Pluck("permissions.name", &permissions).Error
+	if err != nil {
+		return nil, err
+	}
+	return permissions, nil
+}
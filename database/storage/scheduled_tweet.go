@@ -0,0 +1,149 @@
+package storage
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"project/etc/tweettext"
+	"project/models"
+	"time"
+)
+
+type ScheduledTweetRepo struct {
+	db *gorm.DB
+}
+
+func NewScheduledTweetRepo(db *gorm.DB) ScheduledTweet {
+	return &ScheduledTweetRepo{db: db}
+}
+
+func (r *ScheduledTweetRepo) Create(tweet *models.ScheduledTweet) (string, error) {
+	tweet.Id = uuid.New()
+	if err := r.db.Create(tweet).Error; err != nil {
+		return "", err
+	}
+	return tweet.Id.String(), nil
+}
+
+// GetAllForUser lists the user's tweets that are still waiting to be
+// published, the next one first.
+func (r *ScheduledTweetRepo) GetAllForUser(req models.GetScheduledTweetsRequest) (*models.GetScheduledTweetsResponse, error) {
+	var (
+		resp   models.GetScheduledTweetsResponse
+		query  = r.db.Model(&models.ScheduledTweet{}).Where("user_id = ? AND tweet_id IS NULL", req.UserID)
+		offset = (req.Page - 1) * req.Limit
+	)
+
+	if err := query.Session(&gorm.Session{}).Count(&resp.Count).Error; err != nil {
+		return nil, err
+	}
+
+	err := query.Order("publish_at, id").Offset(int(offset)).Limit(int(req.Limit)).Find(&resp.Tweets).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// Reschedule moves a tweet of the user that has not been published yet,
+// clearing a failure so the tweet is tried again. It returns
+// gorm.ErrRecordNotFound when there is no such tweet.
+func (r *ScheduledTweetRepo) Reschedule(id, userID uuid.UUID, publishAt time.Time) error {
+	result := r.db.Model(&models.ScheduledTweet{}).
+		Where("id = ? AND user_id = ? AND tweet_id IS NULL", id, userID).
+		Updates(map[string]interface{}{
+			"publish_at": publishAt,
+			"failed_at":  nil,
+			"last_error": nil,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// Cancel deletes a tweet of the user that has not been published yet. It
+// returns gorm.ErrRecordNotFound when there is no such tweet.
+func (r *ScheduledTweetRepo) Cancel(id, userID uuid.UUID) error {
+	result := r.db.Where("id = ? AND user_id = ? AND tweet_id IS NULL", id, userID).Delete(&models.ScheduledTweet{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// PublishDue publishes up to limit tweets due at now and returns how many
+// it published. The tweets are locked while they are published and skipped
+// by other instances, so every tweet is published exactly once. Each tweet
+// is published in its own savepoint: one that fails is marked as failed
+// and no longer selected, and the others are still published. Tweets of
+// deactivated accounts wait until the account is restored or purged.
+func (r *ScheduledTweetRepo) PublishDue(now time.Time, limit int) (int, error) {
+	published := 0
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var due []models.ScheduledTweet
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("tweet_id IS NULL AND failed_at IS NULL AND publish_at <= ?", now).
+			Where("user_id IN (?)", activeUsers(r.db)).
+			Order("publish_at").
+			Limit(limit).
+			Find(&due).Error
+		if err != nil {
+			return err
+		}
+
+		for _, scheduled := range due {
+			err := tx.Transaction(func(tx *gorm.DB) error {
+				return publishScheduled(tx, &scheduled, now)
+			})
+			if err == nil {
+				published++
+				continue
+			}
+
+			message := err.Error()
+			err = tx.Model(&scheduled).Updates(map[string]interface{}{
+				"failed_at":  now,
+				"last_error": message,
+			}).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return published, err
+}
+
+// publishScheduled creates the tweet of a due scheduled tweet and links the
+// two.
+func publishScheduled(tx *gorm.DB, scheduled *models.ScheduledTweet, now time.Time) error {
+	entities := tweettext.Extract(scheduled.Content)
+	if err := resolveMentions(tx, &entities); err != nil {
+		return err
+	}
+
+	tweet := models.Tweet{
+		UserID:    scheduled.UserID,
+		Content:   scheduled.Content,
+		Entities:  &entities,
+		ImagePath: scheduled.ImagePath,
+		VideoPath: scheduled.VideoPath,
+		Poll:      scheduled.Poll,
+	}
+	if err := createTweet(tx, &tweet); err != nil {
+		return err
+	}
+
+	return tx.Model(scheduled).Updates(map[string]interface{}{
+		"tweet_id":     tweet.Id,
+		"published_at": now,
+	}).Error
+}
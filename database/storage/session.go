@@ -0,0 +1,140 @@
+package storage
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"project/models"
+	"time"
+)
+
+type SessionRepo struct {
+	db *gorm.DB
+}
+
+func NewSessionRepo(db *gorm.DB) Session {
+	return &SessionRepo{db: db}
+}
+
+// Create stores a new session together with its login history entry. The
+// login counts as coming from a new device when the user has logged in
+// before, but never from this device.
+func (r *SessionRepo) Create(session *models.Session, event *models.LoginEvent) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var logins, fromDevice int64
+		if err := tx.Model(&models.LoginEvent{}).Where("user_id = ?", session.UserID).Count(&logins).Error; err != nil {
+			return err
+		}
+		err := tx.Model(&models.LoginEvent{}).
+			Where("user_id = ? AND device_hash = ?", session.UserID, event.DeviceHash).
+			Count(&fromDevice).Error
+		if err != nil {
+			return err
+		}
+
+		if session.Id == uuid.Nil {
+			session.Id = uuid.New()
+		}
+		if err := tx.Create(session).Error; err != nil {
+			return err
+		}
+
+		event.Id = uuid.New()
+		event.UserID = session.UserID
+		event.SessionID = session.Id
+		event.NewDevice = logins > 0 && fromDevice == 0
+		return tx.Create(event).Error
+	})
+}
+
+func (r *SessionRepo) Get(id uuid.UUID) (*models.Session, error) {
+	var session models.Session
+	if err := r.db.Where("id = ?", id).First(&session).Error; err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+// GetAllForUser returns the sessions that are neither revoked nor idle
+// since before activeSince.
+func (r *SessionRepo) GetAllForUser(userID uuid.UUID, activeSince time.Time) ([]models.Session, error) {
+	var sessions []models.Session
+	err := r.db.Where("user_id = ? AND revoked_at IS NULL AND last_seen_at > ?", userID, activeSince).
+		Order("last_seen_at DESC").
+		Find(&sessions).Error
+	if err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+
+// Seen updates the last-seen time. It returns gorm.ErrRecordNotFound when
+// the session does not exist.
+func (r *SessionRepo) Seen(id uuid.UUID, at time.Time) error {
+	res := r.db.Model(&models.Session{}).Where("id = ?", id).Update("last_seen_at", at)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// Revoke ends the session of userID and revokes its refresh tokens. It
+// returns gorm.ErrRecordNotFound when there is no such active session.
+func (r *SessionRepo) Revoke(id, userID uuid.UUID) error {
+	var found bool
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		res := tx.Model(&models.Session{}).
+			Where("id = ? AND user_id = ? AND revoked_at IS NULL", id, userID).
+			Update("revoked_at", now)
+		if res.Error != nil {
+			return res.Error
+		}
+		found = res.RowsAffected > 0
+
+		return tx.Model(&models.RefreshToken{}).
+			Where("family_id = ? AND user_id = ? AND revoked_at IS NULL", id, userID).
+			Update("revoked_at", now).Error
+	})
+	if err == nil && !found {
+		return gorm.ErrRecordNotFound
+	}
+	return err
+}
+
+func (r *SessionRepo) RevokeAllForUser(userID uuid.UUID) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		err := tx.Model(&models.Session{}).
+			Where("user_id = ? AND revoked_at IS NULL", userID).
+			Update("revoked_at", now).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Model(&models.RefreshToken{}).
+			Where("user_id = ? AND revoked_at IS NULL", userID).
+			Update("revoked_at", now).Error
+	})
+}
+
+func (r *SessionRepo) GetLoginHistory(req models.GetLoginHistoryRequest) (*models.GetLoginHistoryResponse, error) {
+	var (
+		resp   models.GetLoginHistoryResponse
+		query  = r.db.Model(&models.LoginEvent{}).Where("user_id = ?", req.UserID)
+		offset = (req.Page - 1) * req.Limit
+	)
+
+	if err := query.Count(&resp.Count).Error; err != nil {
+		return nil, err
+	}
+
+	err := query.Order("created_at DESC").Offset(int(offset)).Limit(int(req.Limit)).Find(&resp.Logins).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
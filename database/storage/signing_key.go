@@ -0,0 +1,58 @@
+package storage
+
+import (
+	"errors"
+	"gorm.io/gorm"
+	"project/models"
+	"time"
+)
+
+// signingKeyLock is the advisory lock taken while appending a signing key, so
+// that instances starting or rotating at the same time agree on one key.
+const signingKeyLock = 7291001
+
+type SigningKeyRepo struct {
+	db *gorm.DB
+}
+
+func NewSigningKeyRepo(db *gorm.DB) SigningKey {
+	return &SigningKeyRepo{db: db}
+}
+
+func (r *SigningKeyRepo) GetAllVerifiable(now time.Time) ([]models.SigningKey, error) {
+	var keys []models.SigningKey
+	err := r.db.Where("verify_until > ?", now).Order("sign_until").Find(&keys).Error
+	return keys, err
+}
+
+// Append stores key unless latestKid is no longer the key that signs the
+// longest, which means another instance has already rotated. It reports
+// whether key was stored.
+func (r *SigningKeyRepo) Append(key *models.SigningKey, latestKid string) (bool, error) {
+	stored := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", signingKeyLock).Error; err != nil {
+			return err
+		}
+
+		var latest models.SigningKey
+		err := tx.Order("sign_until DESC").First(&latest).Error
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			if latestKid != "" {
+				return nil
+			}
+		case err != nil:
+			return err
+		case latest.Kid != latestKid:
+			return nil
+		}
+
+		if err := tx.Create(key).Error; err != nil {
+			return err
+		}
+		stored = true
+		return nil
+	})
+	return stored, err
+}
@@ -1,11 +1,16 @@
 package storage
 
 import (
+	"errors"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	"project/models"
+	"time"
 )
 
+var ErrTweetEdited = errors.New("tweet has been edited in the meantime")
+
 type TweetRepo struct {
 	db *gorm.DB
 }
@@ -16,33 +21,235 @@ func NewTweetRepo(db *gorm.DB) Tweet {
 	}
 }
 
+// Create stores the tweet and counts it on the tweet it replies to, retweets
+// or quotes. The kind follows from which of those is set. The caller sets
+// ConversationID of replies; other tweets start their own.
 func (r *TweetRepo) Create(tweet *models.Tweet) (string, error) {
-	id := uuid.New()
-	tweet.Id = id
-	if err := r.db.Create(tweet).Error; err != nil {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		return createTweet(tx, tweet)
+	})
+	if err != nil {
 		return "", err
 	}
 
-	return id.String(), nil
+	return tweet.Id.String(), nil
 }
 
-func (r *TweetRepo) Update(tweet *models.Tweet) error {
-	if err := r.db.Omit("UserID").Save(tweet).Error; err != nil {
+// createTweet is Create within a transaction the caller owns.
+func createTweet(tx *gorm.DB, tweet *models.Tweet) error {
+	tweet.Id = uuid.New()
+	tweet.Kind = tweetKind(tweet)
+	if tweet.InReplyToID == nil {
+		tweet.ConversationID = tweet.Id
+	}
+
+	if err := tx.Create(tweet).Error; err != nil {
+		return err
+	}
+	if err := linkHashtags(tx, tweet); err != nil {
+		return err
+	}
+	if err := linkMentions(tx, tweet); err != nil {
 		return err
 	}
+	if tweet.Poll != nil {
+		if err := createPoll(tx, tweet.Id, *tweet.Poll); err != nil {
+			return err
+		}
+	}
+
+	if column, parentID := tweetCounter(tweet); parentID != nil {
+		return tx.Model(&models.Tweet{}).
+			Where("id = ?", *parentID).
+			Update(column, gorm.Expr(column+" + 1")).Error
+	}
 	return nil
 }
 
-func (r *TweetRepo) Delete(req models.RequestId) error {
-	if err := r.db.Where("id = ?", req.Id).Delete(&models.Tweet{}).Error; err != nil {
+func tweetKind(tweet *models.Tweet) string {
+	switch {
+	case tweet.InReplyToID != nil:
+		return models.TweetKindReply
+	case tweet.RetweetID != nil:
+		return models.TweetKindRetweet
+	case tweet.QuotedTweetID != nil:
+		return models.TweetKindQuote
+	}
+	return models.TweetKindOriginal
+}
+
+// tweetCounter returns the counter column a tweet adds to and the tweet
+// holding it, which is nil for original tweets.
+func tweetCounter(tweet *models.Tweet) (string, *uuid.UUID) {
+	switch tweet.Kind {
+	case models.TweetKindReply:
+		return "reply_count", tweet.InReplyToID
+	case models.TweetKindRetweet:
+		return "retweet_count", tweet.RetweetID
+	case models.TweetKindQuote:
+		return "quote_count", tweet.QuotedTweetID
+	}
+	return "", nil
+}
+
+// Update saves an edit of the tweet and keeps the version it replaces as a
+// revision. It fails with ErrTweetEdited when the tweet was edited since it
+// was loaded. The kind of a tweet, what it refers to and its counters never
+// change through an edit.
+func (r *TweetRepo) Update(tweet *models.Tweet) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var current models.Tweet
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ?", tweet.Id).
+			First(&current).Error
+		if err != nil {
+			return err
+		}
+		if current.EditCount != tweet.EditCount {
+			return ErrTweetEdited
+		}
+
+		postedAt := current.CreatedAt
+		if current.EditedAt != nil {
+			postedAt = *current.EditedAt
+		}
+		revision := models.TweetRevision{
+			Id:        uuid.New(),
+			TweetID:   current.Id,
+			Version:   current.EditCount + 1,
+			Content:   current.Content,
+			ImagePath: current.ImagePath,
+			VideoPath: current.VideoPath,
+			CreatedAt: postedAt,
+		}
+		if err := tx.Create(&revision).Error; err != nil {
+			return err
+		}
+
+		now := time.Now()
+		tweet.EditCount = current.EditCount + 1
+		tweet.EditedAt = &now
+		err = tx.Model(&models.Tweet{}).
+			Where("id = ?", tweet.Id).
+			Select("Content", "Entities", "ImagePath", "VideoPath", "EditCount", "EditedAt").
+			Updates(tweet).Error
+		if err != nil {
+			return err
+		}
+
+		tweet.CreatedAt = current.CreatedAt
+		if err := linkHashtags(tx, tweet); err != nil {
+			return err
+		}
+		return linkMentions(tx, tweet)
+	})
+}
+
+// GetRevisions returns the versions edits of the tweet replaced, oldest
+// first.
+func (r *TweetRepo) GetRevisions(tweetID uuid.UUID) ([]models.TweetRevision, error) {
+	var revisions []models.TweetRevision
+	err := r.db.Where("tweet_id = ?", tweetID).Order("version").Find(&revisions).Error
+	return revisions, err
+}
+
+// Retweet shares the tweet for the user. Retweeting a tweet again returns
+// the existing retweet.
+func (r *TweetRepo) Retweet(userID, tweetID uuid.UUID) (string, error) {
+	retweet := models.Tweet{
+		Id:        uuid.New(),
+		UserID:    userID,
+		Kind:      models.TweetKindRetweet,
+		RetweetID: &tweetID,
+	}
+	retweet.ConversationID = retweet.Id
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Clauses(clause.OnConflict{
+			Columns:     []clause.Column{{Name: "user_id"}, {Name: "retweet_id"}},
+			TargetWhere: clause.Where{Exprs: []clause.Expression{clause.Expr{SQL: "retweet_id IS NOT NULL AND deleted_at IS NULL"}}},
+			DoNothing:   true,
+		}).Create(&retweet)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return tx.Where("user_id = ? AND retweet_id = ?", userID, tweetID).First(&retweet).Error
+		}
+
+		return tx.Model(&models.Tweet{}).
+			Where("id = ?", tweetID).
+			Update("retweet_count", gorm.Expr("retweet_count + 1")).Error
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return retweet.Id.String(), nil
+}
+
+// Unretweet removes the user's retweet of the tweet, if there is one. Like
+// Retweet, it resolves a retweet to the tweet it shares.
+func (r *TweetRepo) Unretweet(userID, tweetID uuid.UUID) error {
+	var shared models.Tweet
+	err := r.db.Select("id", "kind", "retweet_id").Where("id = ?", tweetID).First(&shared).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
 		return err
 	}
-	return nil
+	if err == nil && shared.Kind == models.TweetKindRetweet && shared.RetweetID != nil {
+		tweetID = *shared.RetweetID
+	}
+
+	var retweet models.Tweet
+	err = r.db.Where("user_id = ? AND retweet_id = ?", userID, tweetID).First(&retweet).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	err = r.Delete(models.RequestId{Id: retweet.Id})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	return err
+}
+
+// Delete soft-deletes the tweet so replies to it keep their place in the
+// conversation, and removes it from the counter it added to. Retweets of
+// the tweet are deleted with it.
+func (r *TweetRepo) Delete(req models.RequestId) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var tweet models.Tweet
+		if err := tx.Where("id = ?", req.Id).First(&tweet).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Delete(&tweet).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Where("retweet_id = ?", tweet.Id).Delete(&models.Tweet{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Unscoped().Model(&tweet).Update("retweet_count", 0).Error; err != nil {
+			return err
+		}
+
+		if column, parentID := tweetCounter(&tweet); parentID != nil {
+			return tx.Unscoped().Model(&models.Tweet{}).
+				Where("id = ? AND "+column+" > 0", *parentID).
+				Update(column, gorm.Expr(column+" - 1")).Error
+		}
+		return nil
+	})
 }
 
 func (r *TweetRepo) Get(req models.RequestId) (*models.Tweet, error) {
 	var tweet models.Tweet
-	if err := r.db.Where("id = ?", req.Id).First(&tweet).Error; err != nil {
+	if err := r.db.Where("id = ?", req.Id).Where("user_id IN (?)", activeUsers(r.db)).First(&tweet).Error; err != nil {
 		return nil, err
 	}
 	return &tweet, nil
@@ -51,7 +258,7 @@ func (r *TweetRepo) Get(req models.RequestId) (*models.Tweet, error) {
 func (r *TweetRepo) GetAll(req models.GetAllTweetsRequest) (*models.GetAllTweetsResponse, error) {
 	var (
 		resp   models.GetAllTweetsResponse
-		query  = r.db.Model(&models.Tweet{})
+		query  = r.db.Model(&models.Tweet{}).Where("user_id IN (?)", activeUsers(r.db))
 		offset = (req.Page - 1) * req.Limit
 	)
 
@@ -63,11 +270,32 @@ func (r *TweetRepo) GetAll(req models.GetAllTweetsRequest) (*models.GetAllTweets
 		query = query.Where("user_id = ?", req.UserID)
 	}
 
-	if err := query.Offset(int(offset)).Limit(int(req.Limit)).Find(&resp.Tweets).Error; err != nil {
+	if req.InReplyToID != "" {
+		query = query.Where("in_reply_to_id = ?", req.InReplyToID).Order("created_at")
+	}
+
+	if req.Hashtag != "" {
+		tagged := r.db.Model(&models.TweetHashtag{}).
+			Select("tweet_hashtags.tweet_id").
+			Joins("JOIN hashtags ON hashtags.id = tweet_hashtags.hashtag_id").
+			Where("hashtags.tag = ?", req.Hashtag)
+		query = query.Where("id IN (?)", tagged).Order("created_at DESC, id DESC")
+	}
+
+	if req.MentionedUserID != uuid.Nil {
+		mentioning := r.db.Model(&models.Mention{}).Select("tweet_id").Where("user_id = ?", req.MentionedUserID)
+		query = query.Where("id IN (?)", mentioning).Order("created_at DESC, id DESC")
+	}
+
+	if req.ViewerID != uuid.Nil {
+		query = visibleTo(r.db, query, req.ViewerID, req.UserID == "")
+	}
+
+	if err := query.Session(&gorm.Session{}).Count(&resp.Count).Error; err != nil {
 		return nil, err
 	}
 
-	if err := query.Count(&resp.Count).Error; err != nil {
+	if err := query.Offset(int(offset)).Limit(int(req.Limit)).Find(&resp.Tweets).Error; err != nil {
 		return nil, err
 	}
 
@@ -83,19 +311,85 @@ func (r *TweetRepo) GetTweetsForUser(Id models.RequestId, req models.GetAllTweet
 	subQuery := r.db.Model(&models.Follow{}).Select("followed_id").Where("follower_id = ?", Id.Id)
 
 	query := r.db.Model(&models.Tweet{}).
-		Where("user_id IN (?)", subQuery).
-		Or("user_id = ?", Id.Id).
-		Offset(offset).
-		Limit(int(req.Limit)).
-		Order("created_at DESC")
+		Where("user_id IN (?) OR user_id = ?", subQuery, Id.Id).
+		Where("user_id IN (?)", activeUsers(r.db))
+
+	query = visibleTo(r.db, query, Id.Id, true)
 
-	if err := query.Find(&resp.Tweets).Error; err != nil {
+	if err := query.Session(&gorm.Session{}).Count(&resp.Count).Error; err != nil {
 		return nil, err
 	}
 
-	if err := query.Count(&resp.Count).Error; err != nil {
+	if err := query.Order("created_at DESC").Offset(offset).Limit(int(req.Limit)).Find(&resp.Tweets).Error; err != nil {
 		return nil, err
 	}
 
 	return &resp, nil
 }
+
+// activeUsers selects the ids of accounts whose content may be shown, i.e.
+// that are neither deleted nor deactivated.
+func activeUsers(db *gorm.DB) *gorm.DB {
+	return db.Model(&models.User{}).Select("id").Where("deactivated_at IS NULL")
+}
+
+// visibleTo hides tweets of users who blocked the viewer or were blocked by
+// them and, when mutes is set, of users the viewer muted.
+func visibleTo(db *gorm.DB, query *gorm.DB, viewerID uuid.UUID, mutes bool) *gorm.DB {
+	query = query.
+		Where("user_id NOT IN (?)", db.Model(&models.Block{}).Select("blocked_id").Where("blocker_id = ?", viewerID)).
+		Where("user_id NOT IN (?)", db.Model(&models.Block{}).Select("blocker_id").Where("blocked_id = ?", viewerID))
+
+	if mutes {
+		query = query.Where("user_id NOT IN (?)", db.Model(&models.Mute{}).Select("muted_id").Where("muter_id = ?", viewerID))
+	}
+	return query
+}
+
+// RetweetedIDs returns which of tweetIDs the user has retweeted.
+func (r *TweetRepo) RetweetedIDs(userID uuid.UUID, tweetIDs []uuid.UUID) ([]uuid.UUID, error) {
+	var retweeted []uuid.UUID
+	err := r.db.Model(&models.Tweet{}).
+		Where("user_id = ? AND retweet_id IN ?", userID, tweetIDs).
+		Distinct().
+		Pluck("retweet_id", &retweeted).Error
+	return retweeted, err
+}
+
+// GetConversation returns up to limit tweets of a conversation, oldest
+// first, including deleted ones.
+func (r *TweetRepo) GetConversation(conversationID uuid.UUID, limit int) ([]models.Tweet, error) {
+	var tweets []models.Tweet
+	err := r.db.Unscoped().
+		Where("conversation_id = ?", conversationID).
+		Order("created_at").
+		Limit(limit).
+		Find(&tweets).Error
+	return tweets, err
+}
+
+// VisibleIDs returns which of tweetIDs exist and may be shown to the viewer,
+// which is uuid.Nil for anonymous requests. Mutes are not applied.
+func (r *TweetRepo) VisibleIDs(viewerID uuid.UUID, tweetIDs []uuid.UUID) ([]uuid.UUID, error) {
+	var visible []uuid.UUID
+	err := r.visible(viewerID, tweetIDs).Pluck("id", &visible).Error
+	return visible, err
+}
+
+// GetVisible loads the tweets of tweetIDs that VisibleIDs would return.
+func (r *TweetRepo) GetVisible(viewerID uuid.UUID, tweetIDs []uuid.UUID) ([]models.Tweet, error) {
+	var tweets []models.Tweet
+	err := r.visible(viewerID, tweetIDs).Find(&tweets).Error
+	return tweets, err
+}
+
+func (r *TweetRepo) visible(viewerID uuid.UUID, tweetIDs []uuid.UUID) *gorm.DB {
+	query := r.db.Model(&models.Tweet{}).
+		Where("id IN ?", tweetIDs).
+		Where("user_id IN (?)", activeUsers(r.db))
+
+	if viewerID != uuid.Nil {
+		query = visibleTo(r.db, query, viewerID, false)
+	}
+	return query
+}
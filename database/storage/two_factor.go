@@ -0,0 +1,93 @@
+package storage
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"project/models"
+	"time"
+)
+
+type TwoFactorRepo struct {
+	db *gorm.DB
+}
+
+func NewTwoFactorRepo(db *gorm.DB) TwoFactor {
+	return &TwoFactorRepo{db: db}
+}
+
+func (r *TwoFactorRepo) SetPendingSecret(userID uuid.UUID, secret string) error {
+	return r.db.Model(&models.User{}).
+		Where("id = ? AND totp_enabled_at IS NULL", userID).
+		Updates(map[string]interface{}{
+			"totp_secret":    secret,
+			"totp_last_step": 0,
+		}).Error
+}
+
+// Enable turns two-factor login on and replaces any previous recovery codes.
+// step is the time step of the code that confirmed the enrollment.
+func (r *TwoFactorRepo) Enable(userID uuid.UUID, step int64, codeHashes []string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&models.User{}).Where("id = ?", userID).Updates(map[string]interface{}{
+			"totp_enabled_at": time.Now(),
+			"totp_last_step":  step,
+		}).Error
+		if err != nil {
+			return err
+		}
+
+		return replaceRecoveryCodes(tx, userID, codeHashes)
+	})
+}
+
+func (r *TwoFactorRepo) Disable(userID uuid.UUID) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&models.User{}).Where("id = ?", userID).Updates(map[string]interface{}{
+			"totp_secret":     nil,
+			"totp_enabled_at": nil,
+			"totp_last_step":  0,
+		}).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Where("user_id = ?", userID).Delete(&models.RecoveryCode{}).Error
+	})
+}
+
+// UseStep records that a code from step has been accepted. It returns false
+// when that step, or a later one, was already used, which means the code is
+// being replayed.
+func (r *TwoFactorRepo) UseStep(userID uuid.UUID, step int64) (bool, error) {
+	res := r.db.Model(&models.User{}).
+		Where("id = ? AND totp_last_step < ?", userID, step).
+		Update("totp_last_step", step)
+	return res.RowsAffected > 0, res.Error
+}
+
+func (r *TwoFactorRepo) ConsumeRecoveryCode(userID uuid.UUID, codeHash string) (bool, error) {
+	res := r.db.Model(&models.RecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, codeHash).
+		Update("used_at", time.Now())
+	return res.RowsAffected > 0, res.Error
+}
+
+func (r *TwoFactorRepo) SetRequired(userID uuid.UUID, required bool) error {
+	return r.db.Model(&models.User{}).Where("id = ?", userID).Update("two_factor_required", required).Error
+}
+
+func replaceRecoveryCodes(tx *gorm.DB, userID uuid.UUID, codeHashes []string) error {
+	if err := tx.Where("user_id = ?", userID).Delete(&models.RecoveryCode{}).Error; err != nil {
+		return err
+	}
+
+	codes := make([]models.RecoveryCode, 0, len(codeHashes))
+	for _, hash := range codeHashes {
+		codes = append(codes, models.RecoveryCode{
+			Id:       uuid.New(),
+			UserID:   userID,
+			CodeHash: hash,
+		})
+	}
+	return tx.Create(&codes).Error
+}
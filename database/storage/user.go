@@ -1,9 +1,14 @@
 package storage
 
 import (
+	"fmt"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"project/etc"
+	"project/etc/tweettext"
 	"project/models"
+	"time"
 )
 
 type UserRepo struct {
@@ -17,15 +22,28 @@ func NewUserRepo(db *gorm.DB) User {
 func (r *UserRepo) Create(user *models.User) (string, error) {
 	id := uuid.New()
 	user.Id = id
-	if err := r.db.Create(user).Error; err != nil {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(user).Error; err != nil {
+			return err
+		}
+
+		return tx.Exec(`INSERT INTO user_roles (user_id, role_id, created_at)
+			SELECT ?, id, NOW() FROM roles WHERE name = ?`, id, models.RoleUser).Error
+	})
+	if err != nil {
 		return "", err
 	}
 
 	return id.String(), nil
 }
 
+// Update only touches the profile fields. Password and e-mail have their own
+// flows and must never be overwritten from a profile update.
 func (r *UserRepo) Update(user *models.User) error {
-	if err := r.db.Omit("Password").Save(user).Error; err != nil {
+	err := r.db.Model(user).
+		Select("Name", "Bio", "Username", "ProfileImage").
+		Updates(user).Error
+	if err != nil {
 		return err
 	}
 
@@ -56,6 +74,8 @@ func (r *UserRepo) GetAll(req models.GetAllUsersRequest) (*models.GetAllUsersRes
 		offset = (req.Page - 1) * req.Limit
 	)
 
+	query = query.Where("users.deactivated_at IS NULL")
+
 	if req.Search != "" {
 		query = query.Where("username ILIKE ?", "%"+req.Search+"%")
 	}
@@ -66,14 +86,18 @@ func (r *UserRepo) GetAll(req models.GetAllUsersRequest) (*models.GetAllUsersRes
 	} else if req.Following != uuid.Nil {
 		query.Joins("left join follows on follows.follower_id = users.id").
 			Where("follows.follower_id = ?", req.Following)
+	} else if req.Retweeted != uuid.Nil {
+		query = query.Joins("join tweets on tweets.user_id = users.id").
+			Where("tweets.retweet_id = ? AND tweets.deleted_at IS NULL", req.Retweeted).
+			Order("tweets.created_at DESC")
 	}
 
-	err := query.Offset(int(offset)).Limit(int(req.Limit)).Find(&resp.Users).Error
+	err := query.Session(&gorm.Session{}).Count(&resp.Count).Error
 	if err != nil {
 		return nil, err
 	}
 
-	err = query.Count(&resp.Count).Error
+	err = query.Offset(int(offset)).Limit(int(req.Limit)).Find(&resp.Users).Error
 	if err != nil {
 		return nil, err
 	}
@@ -81,6 +105,13 @@ func (r *UserRepo) GetAll(req models.GetAllUsersRequest) (*models.GetAllUsersRes
 	return &resp, nil
 }
 
+// GetByIDs loads the active users among ids.
+func (r *UserRepo) GetByIDs(ids []uuid.UUID) ([]models.User, error) {
+	var users []models.User
+	err := r.db.Where("id IN ? AND deactivated_at IS NULL", ids).Find(&users).Error
+	return users, err
+}
+
 func (r *UserRepo) GetByUsername(username string) (*models.User, error) {
 	var user models.User
 	err := r.db.Where("username = ?", username).First(&user).Error
@@ -90,3 +121,273 @@ func (r *UserRepo) GetByUsername(username string) (*models.User, error) {
 
 	return &user, err
 }
+
+// ResolveMentions links the mentions of entities to the active accounts
+// they name, ignoring case, and drops mentions of any other username.
+func (r *UserRepo) ResolveMentions(entities *tweettext.Entities) error {
+	return resolveMentions(r.db, entities)
+}
+
+func (r *UserRepo) UpdatePassword(id uuid.UUID, passwordHash string) error {
+	return r.db.Model(&models.User{}).Where("id = ?", id).Update("password", passwordHash).Error
+}
+
+// ReplacePasswordHash swaps the hash of an unchanged password for one with
+// current parameters. It does nothing if the password was changed meanwhile.
+func (r *UserRepo) ReplacePasswordHash(id uuid.UUID, oldHash, newHash string) error {
+	return r.db.Model(&models.User{}).Where("id = ? AND password = ?", id, oldHash).Update("password", newHash).Error
+}
+
+func (r *UserRepo) GetByEmail(email string) (*models.User, error) {
+	var user models.User
+	if err := r.db.Where("email = ?", etc.NormalizeEmail(email)).First(&user).Error; err != nil {
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+// GetByLogin looks a user up by e-mail when login looks like one and by
+// username otherwise.
+func (r *UserRepo) GetByLogin(login string) (*models.User, error) {
+	if etc.IsEmail(login) {
+		return r.GetByEmail(login)
+	}
+	return r.GetByUsername(login)
+}
+
+func (r *UserRepo) MarkEmailVerified(id uuid.UUID) error {
+	return r.db.Model(&models.User{}).Where("id = ?", id).Update("email_verified_at", time.Now()).Error
+}
+
+// RegisterFailedLogin increments the consecutive failure counter of the user
+// and returns its new value.
+func (r *UserRepo) RegisterFailedLogin(id uuid.UUID) (int, error) {
+	var user models.User
+	err := r.db.Model(&user).
+		Clauses(clause.Returning{Columns: []clause.Column{{Name: "failed_logins"}}}).
+		Where("id = ?", id).
+		Update("failed_logins", gorm.Expr("failed_logins + 1")).Error
+	if err != nil {
+		return 0, err
+	}
+
+	return user.FailedLogins, nil
+}
+
+func (r *UserRepo) LockUntil(id uuid.UUID, until time.Time) error {
+	return r.db.Model(&models.User{}).Where("id = ?", id).Update("locked_until", until).Error
+}
+
+func (r *UserRepo) ResetFailedLogins(id uuid.UUID) error {
+	return r.db.Model(&models.User{}).
+		Where("id = ? AND (failed_logins > 0 OR locked_until IS NOT NULL)", id).
+		Updates(map[string]interface{}{
+			"failed_logins": 0,
+			"locked_until":  nil,
+		}).Error
+}
+
+// Deactivate hides the account. by is nil when the user deactivated it
+// themselves.
+func (r *UserRepo) Deactivate(id uuid.UUID, by *uuid.UUID) error {
+	return r.db.Model(&models.User{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"deactivated_at": time.Now(),
+		"deactivated_by": by,
+	}).Error
+}
+
+func (r *UserRepo) Reactivate(id uuid.UUID) error {
+	return r.db.Model(&models.User{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"deactivated_at": nil,
+		"deactivated_by": nil,
+	}).Error
+}
+
+func (r *UserRepo) GetDeactivatedBefore(cutoff time.Time, limit int) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	err := r.db.Unscoped().Model(&models.User{}).
+		Where("deactivated_at < ?", cutoff).
+		Order("deactivated_at").
+		Limit(limit).
+		Pluck("id", &ids).Error
+	return ids, err
+}
+
+// Purge permanently deletes the user together with everything they created
+// or that refers to them, in one transaction. It returns the paths of the
+// media attached to the deleted tweets and profile that no other user refers
+// to, so the files can be removed as well.
+func (r *UserRepo) Purge(id uuid.UUID) ([]string, error) {
+	var media []string
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var user models.User
+		if err := tx.Unscoped().Where("id = ?", id).First(&user).Error; err != nil {
+			return err
+		}
+		if user.ProfileImage != nil {
+			media = append(media, *user.ProfileImage)
+		}
+
+		var tweets []models.Tweet
+		if err := tx.Unscoped().Where("user_id = ?", id).Find(&tweets).Error; err != nil {
+			return err
+		}
+		for _, tweet := range tweets {
+			if tweet.ImagePath != nil {
+				media = append(media, *tweet.ImagePath)
+			}
+			if tweet.VideoPath != nil {
+				media = append(media, *tweet.VideoPath)
+			}
+		}
+
+		exclusive, err := exclusiveMedia(tx, id, media)
+		if err != nil {
+			return err
+		}
+		media = exclusive
+
+		// Replies, retweets and quotes that are still counted on other users'
+		// tweets stop counting.
+		counters := [][2]string{
+			{"reply_count", "in_reply_to_id"},
+			{"retweet_count", "retweet_id"},
+			{"quote_count", "quoted_tweet_id"},
+		}
+		for _, counter := range counters {
+			err := tx.Exec(fmt.Sprintf(`UPDATE tweets SET %[1]s = GREATEST(tweets.%[1]s - counted.n, 0)
+				FROM (SELECT %[2]s AS tweet_id, COUNT(*) AS n FROM tweets
+					WHERE user_id = ? AND %[2]s IS NOT NULL AND deleted_at IS NULL
+					GROUP BY %[2]s) AS counted
+				WHERE tweets.id = counted.tweet_id`, counter[0], counter[1]), id).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		// Other users' retweets of the user's tweets go with them.
+		tweetIDs := tx.Unscoped().Model(&models.Tweet{}).Select("id").Where("user_id = ?", id)
+		retweetIDs := tx.Unscoped().Model(&models.Tweet{}).Select("id").Where("retweet_id IN (?)", tweetIDs)
+		err = tx.Where("user_id = ? OR tweet_id IN (?) OR tweet_id IN (?)", id, tweetIDs, retweetIDs).
+			Delete(&models.Like{}).Error
+		if err != nil {
+			return err
+		}
+		if err := tx.Where("tweet_id IN (?)", tweetIDs).Delete(&models.TweetRevision{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("tweet_id IN (?)", tweetIDs).Delete(&models.TweetHashtag{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ? OR tweet_id IN (?)", id, tweetIDs).Delete(&models.Mention{}).Error; err != nil {
+			return err
+		}
+
+		// The user's votes stop counting, and the polls of the user's tweets
+		// go with them.
+		votes := [][2]string{
+			{"poll_options", "option_id"},
+			{"polls", "poll_id"},
+		}
+		for _, counter := range votes {
+			err := tx.Exec(fmt.Sprintf(`UPDATE %[1]s SET vote_count = GREATEST(%[1]s.vote_count - counted.n, 0)
+				FROM (SELECT %[2]s AS id, COUNT(*) AS n FROM poll_votes WHERE user_id = ? GROUP BY %[2]s) AS counted
+				WHERE %[1]s.id = counted.id`, counter[0], counter[1]), id).Error
+			if err != nil {
+				return err
+			}
+		}
+		pollIDs := tx.Model(&models.Poll{}).Select("id").Where("tweet_id IN (?)", tweetIDs)
+		if err := tx.Where("user_id = ? OR poll_id IN (?)", id, pollIDs).Delete(&models.PollVote{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("poll_id IN (?)", pollIDs).Delete(&models.PollOption{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("tweet_id IN (?)", tweetIDs).Delete(&models.Poll{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Unscoped().Where("user_id = ? OR retweet_id IN (?)", id, tweetIDs).Delete(&models.Tweet{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("follower_id = ? OR followed_id = ?", id, id).Delete(&models.Follow{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("blocker_id = ? OR blocked_id = ?", id, id).Delete(&models.Block{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("muter_id = ? OR muted_id = ?", id, id).Delete(&models.Mute{}).Error; err != nil {
+			return err
+		}
+
+		owned := []interface{}{
+			&models.RefreshToken{},
+			&models.Session{},
+			&models.LoginEvent{},
+			&models.UserToken{},
+			&models.RecoveryCode{},
+			&models.PersonalAccessToken{},
+			&models.LoginLockout{},
+			&models.UserRole{},
+			&models.ScheduledTweet{},
+			&models.Draft{},
+		}
+		for _, model := range owned {
+			if err := tx.Where("user_id = ?", id).Delete(model).Error; err != nil {
+				return err
+			}
+		}
+
+		return tx.Unscoped().Where("id = ?", id).Delete(&models.User{}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return media, nil
+}
+
+// exclusiveMedia keeps the paths among media, once each, that no row of
+// another user refers to. Media paths are set by clients, so a path shared
+// with another account may well be that account's file.
+func exclusiveMedia(tx *gorm.DB, userID uuid.UUID, media []string) ([]string, error) {
+	if len(media) == 0 {
+		return nil, nil
+	}
+
+	references := []struct {
+		model   interface{}
+		owner   string
+		columns []string
+	}{
+		{&models.User{}, "id", []string{"profile_image"}},
+		{&models.Tweet{}, "user_id", []string{"image_path", "video_path"}},
+		{&models.ScheduledTweet{}, "user_id", []string{"image_path", "video_path"}},
+		{&models.Draft{}, "user_id", []string{"image_path", "video_path"}},
+	}
+
+	shared := make(map[string]bool)
+	for _, ref := range references {
+		for _, column := range ref.columns {
+			var paths []string
+			err := tx.Unscoped().Model(ref.model).
+				Where(ref.owner+" <> ? AND "+column+" IN ?", userID, media).
+				Pluck(column, &paths).Error
+			if err != nil {
+				return nil, err
+			}
+			for _, path := range paths {
+				shared[path] = true
+			}
+		}
+	}
+
+	var exclusive []string
+	for _, path := range media {
+		if !shared[path] {
+			exclusive = append(exclusive, path)
+			shared[path] = true
+		}
+	}
+	return exclusive, nil
+}
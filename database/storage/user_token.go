@@ -0,0 +1,62 @@
+package storage
+
+import (
+	"errors"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"project/models"
+	"time"
+)
+
+var ErrUserTokenInvalid = errors.New("token is invalid, expired or already used")
+
+type UserTokenRepo struct {
+	db *gorm.DB
+}
+
+func NewUserTokenRepo(db *gorm.DB) UserToken {
+	return &UserTokenRepo{db: db}
+}
+
+// Create stores token and retires every earlier unused token with the same
+// purpose, so only the most recently sent link works.
+func (r *UserTokenRepo) Create(token *models.UserToken) error {
+	token.Id = uuid.New()
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&models.UserToken{}).
+			Where("user_id = ? AND purpose = ? AND used_at IS NULL", token.UserID, token.Purpose).
+			Update("used_at", time.Now()).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Create(token).Error
+	})
+}
+
+// Consume marks the token as used and returns it. It fails with
+// ErrUserTokenInvalid when the token is unknown, expired or already used.
+func (r *UserTokenRepo) Consume(purpose, hash string) (*models.UserToken, error) {
+	var token models.UserToken
+	err := r.db.Where("token_hash = ? AND purpose = ?", hash, purpose).First(&token).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrUserTokenInvalid
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	res := r.db.Model(&models.UserToken{}).
+		Where("id = ? AND used_at IS NULL AND expires_at > ?", token.Id, now).
+		Update("used_at", now)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	if res.RowsAffected == 0 {
+		return nil, ErrUserTokenInvalid
+	}
+
+	token.UsedAt = &now
+	return &token, nil
+}
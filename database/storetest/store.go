@@ -0,0 +1,347 @@
+// Package storetest provides an in-memory database.IStore for tests. Only
+// the methods the tests rely on are implemented; calling any other method
+// panics, which points at what a new test still has to fake.
+package storetest
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"project/database"
+	"project/database/storage"
+	"project/etc/tweettext"
+	"project/models"
+	"strings"
+	"sync"
+	"time"
+)
+
+type Store struct {
+	database.IStore
+
+	mu            sync.Mutex
+	users         map[uuid.UUID]*models.User
+	tweets        map[uuid.UUID]*models.Tweet
+	permissions   map[uuid.UUID][]string
+	sessions      map[uuid.UUID]*models.Session
+	refreshTokens map[string]*models.RefreshToken
+	signingKeys   []models.SigningKey
+}
+
+func New() *Store {
+	return &Store{
+		users:         make(map[uuid.UUID]*models.User),
+		tweets:        make(map[uuid.UUID]*models.Tweet),
+		permissions:   make(map[uuid.UUID][]string),
+		sessions:      make(map[uuid.UUID]*models.Session),
+		refreshTokens: make(map[string]*models.RefreshToken),
+	}
+}
+
+// AddUser stores a user with a new id and returns it.
+func (s *Store) AddUser(username string) *models.User {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	user := &models.User{Id: uuid.New(), Name: username, Username: username, CreatedAt: time.Now()}
+	s.users[user.Id] = user
+	return user
+}
+
+// AddTweet stores an original tweet of the user with a new id and returns it.
+func (s *Store) AddTweet(userID uuid.UUID, content string) *models.Tweet {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tweet := &models.Tweet{Id: uuid.New(), UserID: userID, Content: content, Kind: models.TweetKindOriginal, CreatedAt: time.Now()}
+	tweet.ConversationID = tweet.Id
+	s.tweets[tweet.Id] = tweet
+	return tweet
+}
+
+// Grant gives the user the permissions, as a role would.
+func (s *Store) Grant(userID uuid.UUID, permissions ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.permissions[userID] = append(s.permissions[userID], permissions...)
+}
+
+func (s *Store) User() storage.User { return users{Store: s} }
+
+func (s *Store) Tweet() storage.Tweet { return tweets{Store: s} }
+
+func (s *Store) Role() storage.Role { return roles{Store: s} }
+
+func (s *Store) Session() storage.Session { return sessions{Store: s} }
+
+func (s *Store) RefreshToken() storage.RefreshToken { return refreshTokens{Store: s} }
+
+func (s *Store) SigningKey() storage.SigningKey { return signingKeys{Store: s} }
+
+type users struct {
+	storage.User
+	*Store
+}
+
+func (r users) Get(req models.RequestId) (*models.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	user, ok := r.users[req.Id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	copied := *user
+	return &copied, nil
+}
+
+func (r users) GetByUsername(username string) (*models.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, user := range r.users {
+		if user.Username == username {
+			copied := *user
+			return &copied, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r users) ResolveMentions(entities *tweettext.Entities) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	mentions := entities.Mentions[:0]
+	for _, mention := range entities.Mentions {
+		for _, user := range r.users {
+			if user.DeactivatedAt == nil && strings.EqualFold(user.Username, mention.Username) {
+				mention.UserID = user.Id.String()
+				mentions = append(mentions, mention)
+				break
+			}
+		}
+	}
+	entities.Mentions = mentions
+	return nil
+}
+
+func (r users) Deactivate(id uuid.UUID, by *uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	user, ok := r.users[id]
+	if !ok {
+		return gorm.ErrRecordNotFound
+	}
+	now := time.Now()
+	user.DeactivatedAt, user.DeactivatedBy = &now, by
+	return nil
+}
+
+type tweets struct {
+	storage.Tweet
+	*Store
+}
+
+func (r tweets) Get(req models.RequestId) (*models.Tweet, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	tweet, ok := r.tweets[req.Id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	copied := *tweet
+	return &copied, nil
+}
+
+func (r tweets) Update(tweet *models.Tweet) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	current, ok := r.tweets[tweet.Id]
+	if !ok {
+		return gorm.ErrRecordNotFound
+	}
+	if current.EditCount != tweet.EditCount {
+		return storage.ErrTweetEdited
+	}
+	updated := *tweet
+	updated.EditCount++
+	r.tweets[tweet.Id] = &updated
+	return nil
+}
+
+func (r tweets) Delete(req models.RequestId) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.tweets, req.Id)
+	return nil
+}
+
+type roles struct {
+	storage.Role
+	*Store
+}
+
+func (r roles) PermissionsForUser(userID uuid.UUID) ([]string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]string(nil), r.permissions[userID]...), nil
+}
+
+type sessions struct {
+	storage.Session
+	*Store
+}
+
+func (r sessions) Create(session *models.Session, _ *models.LoginEvent) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if session.Id == uuid.Nil {
+		session.Id = uuid.New()
+	}
+	copied := *session
+	r.sessions[session.Id] = &copied
+	return nil
+}
+
+func (r sessions) Get(id uuid.UUID) (*models.Session, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	session, ok := r.sessions[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	copied := *session
+	return &copied, nil
+}
+
+func (r sessions) Seen(id uuid.UUID, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	session, ok := r.sessions[id]
+	if !ok {
+		return gorm.ErrRecordNotFound
+	}
+	session.LastSeenAt = at
+	return nil
+}
+
+// Revoke ends the session and revokes its refresh tokens, like the real
+// repository does.
+func (r sessions) Revoke(id, userID uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	for _, token := range r.refreshTokens {
+		if token.FamilyID == id && token.UserID == userID && token.RevokedAt == nil {
+			token.RevokedAt = &now
+		}
+	}
+
+	session, ok := r.sessions[id]
+	if !ok || session.UserID != userID || session.RevokedAt != nil {
+		return gorm.ErrRecordNotFound
+	}
+	session.RevokedAt = &now
+	return nil
+}
+
+func (r sessions) RevokeAllForUser(userID uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	for _, session := range r.sessions {
+		if session.UserID == userID && session.RevokedAt == nil {
+			session.RevokedAt = &now
+		}
+	}
+	for _, token := range r.refreshTokens {
+		if token.UserID == userID && token.RevokedAt == nil {
+			token.RevokedAt = &now
+		}
+	}
+	return nil
+}
+
+type refreshTokens struct {
+	storage.RefreshToken
+	*Store
+}
+
+func (r refreshTokens) Create(token *models.RefreshToken) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	token.Id = uuid.New()
+	copied := *token
+	r.refreshTokens[token.TokenHash] = &copied
+	return nil
+}
+
+func (r refreshTokens) GetByHash(hash string) (*models.RefreshToken, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	token, ok := r.refreshTokens[hash]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	copied := *token
+	return &copied, nil
+}
+
+func (r refreshTokens) Rotate(old *models.RefreshToken, next *models.RefreshToken) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	current, ok := r.refreshTokens[old.TokenHash]
+	if !ok || current.RevokedAt != nil {
+		return storage.ErrRefreshTokenReused
+	}
+
+	now := time.Now()
+	next.Id = uuid.New()
+	current.RevokedAt, current.ReplacedByID = &now, &next.Id
+	copied := *next
+	r.refreshTokens[next.TokenHash] = &copied
+	return nil
+}
+
+type signingKeys struct {
+	storage.SigningKey
+	*Store
+}
+
+func (r signingKeys) GetAllVerifiable(now time.Time) ([]models.SigningKey, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var keys []models.SigningKey
+	for _, key := range r.signingKeys {
+		if key.VerifyUntil.After(now) {
+			keys = append(keys, key)
+		}
+	}
+	return keys, nil
+}
+
+func (r signingKeys) Append(key *models.SigningKey, latestKid string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if n := len(r.signingKeys); n > 0 && r.signingKeys[n-1].Kid != latestKid {
+		return false, nil
+	}
+	r.signingKeys = append(r.signingKeys, *key)
+	return true, nil
+}
@@ -1,21 +0,0 @@
-package etc
-
-import (
-	"errors"
-	"golang.org/x/crypto/bcrypt"
-)
-
-func GeneratePasswordHash(pass string) ([]byte, error) {
-	return bcrypt.GenerateFromPassword([]byte(pass), 10)
-}
-
-func CheckPassword(password, hashedPassword string) (bool, error) {
-	err := bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(password))
-	if err != nil {
-		if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
-			return false, nil
-		}
-		return false, err
-	}
-	return true, nil
-}
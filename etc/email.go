@@ -0,0 +1,15 @@
+package etc
+
+import "strings"
+
+// NormalizeEmail returns the form e-mail addresses are stored and looked up
+// in, so that uniqueness and login ignore case.
+func NormalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// IsEmail reports whether a login identifier is an e-mail address rather than
+// a username. Usernames may not contain "@".
+func IsEmail(login string) bool {
+	return strings.Contains(login, "@")
+}
@@ -0,0 +1,262 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"github.com/dgrijalva/jwt-go"
+	"github.com/google/uuid"
+	"log"
+	"project/database/storage"
+	"project/models"
+	"sync"
+	"time"
+)
+
+const (
+	AlgEdDSA = "EdDSA"
+	AlgRS256 = "RS256"
+)
+
+// keyRefreshInterval is how often keys are reloaded, which is also how long
+// it takes for a key created by another instance to be used here.
+const keyRefreshInterval = time.Minute
+
+type KeyConfig struct {
+	// Algorithm is used for newly generated keys: EdDSA or RS256.
+	Algorithm string
+	// Rotation is how long a key signs tokens before the next one takes over.
+	Rotation time.Duration
+	// Grace is how long a key is still published after it stopped signing.
+	// The next key is also published this long before it starts signing, so
+	// services caching the JWKS see it in time. It must be longer than the
+	// lifetime of any token and longer than consumers cache the JWKS.
+	Grace time.Duration
+	// Issuer is put into the iss claim and required when verifying, if set.
+	Issuer string
+}
+
+// KeyManager signs tokens with the current key pair and verifies them with
+// any key that is still published. Keys are stored in the database so that
+// every instance signs with the same key and serves the same JWKS.
+type KeyManager struct {
+	repo storage.SigningKey
+	cfg  KeyConfig
+
+	mu   sync.RWMutex
+	keys []*signingKey
+}
+
+type signingKey struct {
+	kid         string
+	method      jwt.SigningMethod
+	private     crypto.Signer
+	public      crypto.PublicKey
+	signUntil   time.Time
+	verifyUntil time.Time
+}
+
+func NewKeyManager(repo storage.SigningKey, cfg KeyConfig) (*KeyManager, error) {
+	if _, err := signingMethod(cfg.Algorithm); err != nil {
+		return nil, err
+	}
+	if cfg.Rotation <= cfg.Grace {
+		return nil, errors.New("key rotation period must be longer than the grace period")
+	}
+
+	km := &KeyManager{repo: repo, cfg: cfg}
+	if err := km.Refresh(time.Now()); err != nil {
+		return nil, err
+	}
+	return km, nil
+}
+
+// Run reloads the keys and rotates them when due until ctx is cancelled.
+func (km *KeyManager) Run(ctx context.Context) {
+	ticker := time.NewTicker(keyRefreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			if err := km.Refresh(now); err != nil {
+				log.Printf("Failed to refresh signing keys: %v", err)
+			}
+		}
+	}
+}
+
+// Refresh loads the published keys and creates the next one once the latest
+// key has less than the grace period left to sign.
+func (km *KeyManager) Refresh(now time.Time) error {
+	records, err := km.repo.GetAllVerifiable(now)
+	if err != nil {
+		return err
+	}
+
+	var latest *models.SigningKey
+	if len(records) > 0 {
+		latest = &records[len(records)-1]
+	}
+
+	if latest == nil || latest.SignUntil.Sub(now) <= km.cfg.Grace {
+		latestKid, signFrom := "", now
+		if latest != nil {
+			latestKid = latest.Kid
+			if latest.SignUntil.After(now) {
+				signFrom = latest.SignUntil
+			}
+		}
+
+		next, err := generateSigningKey(km.cfg.Algorithm, signFrom.Add(km.cfg.Rotation), km.cfg.Grace)
+		if err != nil {
+			return err
+		}
+		if _, err := km.repo.Append(next, latestKid); err != nil {
+			return err
+		}
+
+		if records, err = km.repo.GetAllVerifiable(now); err != nil {
+			return err
+		}
+	}
+
+	keys := make([]*signingKey, 0, len(records))
+	for _, record := range records {
+		key, err := parseSigningKey(record)
+		if err != nil {
+			return fmt.Errorf("signing key %s: %w", record.Kid, err)
+		}
+		keys = append(keys, key)
+	}
+
+	km.mu.Lock()
+	km.keys = keys
+	km.mu.Unlock()
+	return nil
+}
+
+// signingKey returns the key that signs at the given time: the one whose
+// signing period ends first without having ended yet.
+func (km *KeyManager) signingKey(now time.Time) (*signingKey, error) {
+	km.mu.RLock()
+	defer km.mu.RUnlock()
+
+	for _, key := range km.keys {
+		if key.signUntil.After(now) {
+			return key, nil
+		}
+	}
+	return nil, errors.New("no signing key available")
+}
+
+// verificationKey is the jwt.Keyfunc used to verify tokens. The algorithm in
+// the header must match the key, so an RSA public key can never be used as
+// an HMAC secret.
+func (km *KeyManager) verificationKey(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	if kid == "" {
+		return nil, ErrInvalidToken
+	}
+
+	km.mu.RLock()
+	defer km.mu.RUnlock()
+
+	now := time.Now()
+	for _, key := range km.keys {
+		if key.kid == kid && key.verifyUntil.After(now) && key.method.Alg() == token.Method.Alg() {
+			return key.public, nil
+		}
+	}
+	return nil, ErrInvalidToken
+}
+
+func signingMethod(alg string) (jwt.SigningMethod, error) {
+	switch alg {
+	case AlgEdDSA:
+		return SigningMethodEdDSA, nil
+	case AlgRS256:
+		return jwt.SigningMethodRS256, nil
+	default:
+		return nil, fmt.Errorf("unsupported signing algorithm %q", alg)
+	}
+}
+
+func generateSigningKey(alg string, signUntil time.Time, grace time.Duration) (*models.SigningKey, error) {
+	var private crypto.Signer
+	var err error
+	switch alg {
+	case AlgEdDSA:
+		_, private, err = ed25519.GenerateKey(rand.Reader)
+	case AlgRS256:
+		private, err = rsa.GenerateKey(rand.Reader, 2048)
+	default:
+		err = fmt.Errorf("unsupported signing algorithm %q", alg)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	der, err := x509.MarshalPKCS8PrivateKey(private)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.SigningKey{
+		Kid:         uuid.NewString(),
+		Algorithm:   alg,
+		PrivateKey:  pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}),
+		SignUntil:   signUntil,
+		VerifyUntil: signUntil.Add(grace),
+	}, nil
+}
+
+func parseSigningKey(record models.SigningKey) (*signingKey, error) {
+	method, err := signingMethod(record.Algorithm)
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(record.PrivateKey)
+	if block == nil {
+		return nil, errors.New("invalid PEM data")
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+
+	private, ok := parsed.(crypto.Signer)
+	if !ok {
+		return nil, errors.New("unsupported private key type")
+	}
+	switch private.(type) {
+	case *rsa.PrivateKey:
+		if record.Algorithm != AlgRS256 {
+			return nil, errors.New("RSA key stored for " + record.Algorithm)
+		}
+	case ed25519.PrivateKey:
+		if record.Algorithm != AlgEdDSA {
+			return nil, errors.New("Ed25519 key stored for " + record.Algorithm)
+		}
+	default:
+		return nil, errors.New("unsupported private key type")
+	}
+
+	return &signingKey{
+		kid:         record.Kid,
+		method:      method,
+		private:     private,
+		public:      private.Public(),
+		signUntil:   record.SignUntil,
+		verifyUntil: record.VerifyUntil,
+	}, nil
+}
@@ -0,0 +1,76 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"project/database/cache"
+	"strconv"
+	"time"
+)
+
+// Revoker keeps track of access tokens that must no longer be accepted even
+// though their signature and expiry are still valid. Entries only need to
+// live as long as the tokens they revoke, so they expire on their own.
+type Revoker struct {
+	cache     cache.Cache
+	accessTTL time.Duration
+}
+
+func NewRevoker(c cache.Cache, accessTTL time.Duration) *Revoker {
+	return &Revoker{cache: c, accessTTL: accessTTL}
+}
+
+func tokenKey(jti string) string { return "revoked:token:" + jti }
+
+func userKey(userID string) string { return "revoked:user:" + userID }
+
+// RevokeToken blocks a single token until it would have expired anyway.
+func (r *Revoker) RevokeToken(ctx context.Context, claims *Claims) error {
+	ttl := time.Until(time.Unix(claims.ExpiresAt, 0))
+	if ttl <= 0 {
+		return nil
+	}
+	return r.cache.Set(ctx, tokenKey(claims.Id), "1", ttl)
+}
+
+// RevokeUser blocks every token issued to the user before now. A token
+// issued within the same millisecond, e.g. by a login right after, stays
+// valid, so a caller that wants its own token revoked revokes it explicitly.
+func (r *Revoker) RevokeUser(ctx context.Context, userID string) error {
+	now := strconv.FormatInt(time.Now().UnixMilli(), 10)
+	return r.cache.Set(ctx, userKey(userID), now, r.accessTTL)
+}
+
+func (r *Revoker) IsRevoked(ctx context.Context, claims *Claims) (bool, error) {
+	_, err := r.cache.Get(ctx, tokenKey(claims.Id))
+	if err == nil {
+		return true, nil
+	}
+	if !errors.Is(err, cache.ErrNotFound) {
+		return false, err
+	}
+
+	value, err := r.cache.Get(ctx, userKey(claims.UserID))
+	if errors.Is(err, cache.ErrNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	revokedAt, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return false, err
+	}
+	return issuedAtMilli(claims) < revokedAt, nil
+}
+
+// issuedAtMilli returns when the token was issued in milliseconds. Tokens
+// without the millisecond claim count as issued at the start of their
+// second, so a revocation in that second still covers them.
+func issuedAtMilli(claims *Claims) int64 {
+	if claims.IssuedAtMilli != 0 {
+		return claims.IssuedAtMilli
+	}
+	return claims.IssuedAt * 1000
+}
@@ -0,0 +1,113 @@
+package auth
+
+import (
+	"context"
+	"github.com/dgrijalva/jwt-go"
+	"project/database/cache"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestIsRevokedByUserCutoff(t *testing.T) {
+	const revokedAt = int64(1700000000500)
+
+	tests := []struct {
+		name   string
+		claims *Claims
+		want   bool
+	}{
+		{
+			name:   "issued earlier in the same second",
+			claims: &Claims{IssuedAtMilli: revokedAt - 1, StandardClaims: jwt.StandardClaims{IssuedAt: revokedAt / 1000}},
+			want:   true,
+		},
+		{
+			name:   "issued in the same millisecond",
+			claims: &Claims{IssuedAtMilli: revokedAt, StandardClaims: jwt.StandardClaims{IssuedAt: revokedAt / 1000}},
+			want:   false,
+		},
+		{
+			name:   "issued later",
+			claims: &Claims{IssuedAtMilli: revokedAt + 1, StandardClaims: jwt.StandardClaims{IssuedAt: revokedAt / 1000}},
+			want:   false,
+		},
+		{
+			name:   "without milliseconds, issued in the same second",
+			claims: &Claims{StandardClaims: jwt.StandardClaims{IssuedAt: revokedAt / 1000}},
+			want:   true,
+		},
+		{
+			name:   "without milliseconds, issued in the next second",
+			claims: &Claims{StandardClaims: jwt.StandardClaims{IssuedAt: revokedAt/1000 + 1}},
+			want:   false,
+		},
+	}
+
+	ctx := context.Background()
+	kv := cache.NewMemory()
+	revoker := NewRevoker(kv, time.Hour)
+	if err := kv.Set(ctx, userKey("user"), strconv.FormatInt(revokedAt, 10), time.Hour); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range tests {
+		tt.claims.UserID = "user"
+		tt.claims.Id = tt.name
+		revoked, err := revoker.IsRevoked(ctx, tt.claims)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if revoked != tt.want {
+			t.Errorf("%s: IsRevoked = %v, want %v", tt.name, revoked, tt.want)
+		}
+	}
+}
+
+func TestRevokeUser(t *testing.T) {
+	ctx := context.Background()
+	revoker := NewRevoker(cache.NewMemory(), time.Hour)
+
+	issued := time.Now().Add(-time.Millisecond)
+	claims := &Claims{
+		UserID:         "user",
+		IssuedAtMilli:  issued.UnixMilli(),
+		StandardClaims: jwt.StandardClaims{Id: "token", IssuedAt: issued.Unix()},
+	}
+	if err := revoker.RevokeUser(ctx, "user"); err != nil {
+		t.Fatal(err)
+	}
+
+	revoked, err := revoker.IsRevoked(ctx, claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !revoked {
+		t.Error("token issued before RevokeUser is not revoked")
+	}
+}
+
+func TestRevokeToken(t *testing.T) {
+	ctx := context.Background()
+	revoker := NewRevoker(cache.NewMemory(), time.Hour)
+
+	claims := &Claims{
+		UserID: "user",
+		StandardClaims: jwt.StandardClaims{
+			Id:        "token",
+			IssuedAt:  time.Now().Unix(),
+			ExpiresAt: time.Now().Add(time.Minute).Unix(),
+		},
+	}
+	if err := revoker.RevokeToken(ctx, claims); err != nil {
+		t.Fatal(err)
+	}
+
+	revoked, err := revoker.IsRevoked(ctx, claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !revoked {
+		t.Error("revoked token is accepted")
+	}
+}
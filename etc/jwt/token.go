@@ -1,40 +1,115 @@
 package auth
 
 import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
 	"github.com/dgrijalva/jwt-go"
-	"os"
+	"github.com/google/uuid"
 	"time"
 )
 
-var JwtSecret = []byte(os.Getenv("SECRET_KEY"))
+const (
+	// TokenAccess is the only token type accepted by regular endpoints.
+	TokenAccess = "access"
+	// TokenChallenge proves the password step of a two-factor login.
+	TokenChallenge = "2fa_challenge"
+	// TokenEnrollment lets a user who is required to use two-factor
+	// authentication set it up, and nothing else.
+	TokenEnrollment = "2fa_enrollment"
+)
+
+var ErrInvalidToken = errors.New("invalid or expired token")
 
+// Claims deliberately carry no role: permissions are looked up on every
+// request so that granting or revoking a role takes effect immediately.
 type Claims struct {
-	UserID string `json:"user_id"`
-	Role   string `json:"role"`
+	UserID    string `json:"user_id"`
+	TokenType string `json:"token_type"`
+	// SessionID is only set on access tokens.
+	SessionID string `json:"sid,omitempty"`
+	// IssuedAtMilli is IssuedAt in milliseconds, precise enough to tell a
+	// token from a revocation in the same second.
+	IssuedAtMilli int64 `json:"iat_ms,omitempty"`
 	jwt.StandardClaims
 }
 
-func GenerateToken(userID string, username string) (string, error) {
-	var role string
-	if username == "admin" {
-		role = "admin"
-	} else {
-		role = "user"
+// GenerateToken issues an access token for the given session.
+func (km *KeyManager) GenerateToken(userID string, sessionID string, ttl time.Duration) (string, error) {
+	return km.generate(userID, TokenAccess, sessionID, ttl)
+}
+
+func (km *KeyManager) GenerateTypedToken(userID string, tokenType string, ttl time.Duration) (string, error) {
+	return km.generate(userID, tokenType, "", ttl)
+}
+
+func (km *KeyManager) generate(userID string, tokenType string, sessionID string, ttl time.Duration) (string, error) {
+	now := time.Now()
+	key, err := km.signingKey(now)
+	if err != nil {
+		return "", err
 	}
-	expirationTime := time.Now().Add(24 * time.Hour)
+
 	claims := &Claims{
-		UserID: userID,
-		Role:   role,
+		UserID:        userID,
+		TokenType:     tokenType,
+		SessionID:     sessionID,
+		IssuedAtMilli: now.UnixMilli(),
 		StandardClaims: jwt.StandardClaims{
-			ExpiresAt: expirationTime.Unix(),
+			Id:        uuid.NewString(),
+			Issuer:    km.cfg.Issuer,
+			IssuedAt:  now.Unix(),
+			ExpiresAt: now.Add(ttl).Unix(),
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	tokenString, err := token.SignedString(JwtSecret)
+	token := jwt.NewWithClaims(key.method, claims)
+	token.Header["kid"] = key.kid
+	tokenString, err := token.SignedString(key.private)
 	if err != nil {
 		return "", err
 	}
 
 	return tokenString, nil
 }
+
+// ParseToken verifies the signature, expiry and issuer of tokenString and
+// checks that it is one of the allowed token types.
+func (km *KeyManager) ParseToken(tokenString string, allowed ...string) (*Claims, error) {
+	claims := &Claims{}
+	token, err := jwt.ParseWithClaims(tokenString, claims, km.verificationKey)
+	if err != nil || !token.Valid {
+		return nil, ErrInvalidToken
+	}
+	if km.cfg.Issuer != "" && claims.Issuer != km.cfg.Issuer {
+		return nil, ErrInvalidToken
+	}
+
+	for _, tokenType := range allowed {
+		if claims.TokenType == tokenType {
+			return claims, nil
+		}
+	}
+	return nil, ErrInvalidToken
+}
+
+// PersonalTokenPrefix marks personal access tokens so they can be told apart
+// from JWTs without a database lookup.
+const PersonalTokenPrefix = "pat_"
+
+// GenerateOpaqueToken returns a random URL-safe token. Only its hash
+// (see HashToken) should ever be persisted.
+func GenerateOpaqueToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,123 @@
+package auth
+
+import (
+	"github.com/dgrijalva/jwt-go"
+	"github.com/google/uuid"
+	"project/database/storetest"
+	"testing"
+	"time"
+)
+
+func newTestKeyManager(t *testing.T, issuer string) *KeyManager {
+	t.Helper()
+
+	km, err := NewKeyManager(storetest.New().SigningKey(), KeyConfig{
+		Algorithm: AlgEdDSA,
+		Rotation:  24 * time.Hour,
+		Grace:     time.Hour,
+		Issuer:    issuer,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return km
+}
+
+func TestParseToken(t *testing.T) {
+	km := newTestKeyManager(t, "twitter")
+	sessionID := uuid.NewString()
+
+	token, err := km.GenerateToken("user", sessionID, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	claims, err := km.ParseToken(token, TokenAccess)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if claims.UserID != "user" || claims.SessionID != sessionID || claims.Issuer != "twitter" {
+		t.Errorf("unexpected claims %+v", claims)
+	}
+
+	if _, err := km.ParseToken(token, TokenChallenge); err != ErrInvalidToken {
+		t.Errorf("access token accepted as %s", TokenChallenge)
+	}
+}
+
+func TestParseTokenRejects(t *testing.T) {
+	km := newTestKeyManager(t, "twitter")
+	now := time.Now()
+
+	claims := func() *Claims {
+		return &Claims{
+			UserID:    "user",
+			TokenType: TokenAccess,
+			StandardClaims: jwt.StandardClaims{
+				Id:        uuid.NewString(),
+				Issuer:    "twitter",
+				IssuedAt:  now.Unix(),
+				ExpiresAt: now.Add(time.Minute).Unix(),
+			},
+		}
+	}
+	signed := func(c *Claims) string {
+		key, err := km.signingKey(now)
+		if err != nil {
+			t.Fatal(err)
+		}
+		token := jwt.NewWithClaims(key.method, c)
+		token.Header["kid"] = key.kid
+		s, err := token.SignedString(key.private)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return s
+	}
+
+	hmacToken := func(withKid bool) string {
+		token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims())
+		if withKid {
+			key, _ := km.signingKey(now)
+			token.Header["kid"] = key.kid
+		}
+		s, err := token.SignedString([]byte("old shared secret"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return s
+	}
+
+	expired := claims()
+	expired.ExpiresAt = now.Add(-time.Minute).Unix()
+	otherIssuer := claims()
+	otherIssuer.Issuer = "someone-else"
+	noIssuer := claims()
+	noIssuer.Issuer = ""
+
+	otherKeys := newTestKeyManager(t, "twitter")
+	foreign, err := otherKeys.GenerateToken("user", "", time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{"HS256 without kid", hmacToken(false)},
+		{"HS256 with a known kid", hmacToken(true)},
+		{"expired", signed(expired)},
+		{"other issuer", signed(otherIssuer)},
+		{"missing issuer", signed(noIssuer)},
+		{"signed by an unknown key", foreign},
+		{"malformed", "not.a.token"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := km.ParseToken(tt.token, TokenAccess); err != ErrInvalidToken {
+				t.Errorf("got %v, want ErrInvalidToken", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package mailer
+
+import (
+	"context"
+	"log"
+)
+
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer delivers transactional messages such as password reset links.
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+type Config struct {
+	Driver       string
+	From         string
+	SMTPHost     string
+	SMTPPort     string
+	SMTPUsername string
+	SMTPPassword string
+	OutboxDir    string
+}
+
+// New returns an SMTP mailer when the driver is "smtp" and an outbox mailer,
+// which never leaves the machine, for anything else.
+func New(cfg Config) Mailer {
+	if cfg.Driver == "smtp" {
+		return NewSMTP(cfg.SMTPHost, cfg.SMTPPort, cfg.SMTPUsername, cfg.SMTPPassword, cfg.From)
+	}
+
+	log.Println("Using outbox mailer, messages are not delivered")
+	return NewOutbox(cfg.OutboxDir)
+}
@@ -0,0 +1,37 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Outbox keeps messages on the local machine. With a directory every message
+// is written to its own file there, otherwise it is printed to the log.
+type Outbox struct {
+	dir string
+}
+
+func NewOutbox(dir string) *Outbox {
+	return &Outbox{dir: dir}
+}
+
+func (o *Outbox) Send(_ context.Context, msg Message) error {
+	content := fmt.Sprintf("To: %s\nSubject: %s\n\n%s\n", msg.To, msg.Subject, msg.Body)
+
+	if o.dir == "" {
+		log.Printf("Outbox message:\n%s", content)
+		return nil
+	}
+
+	if err := os.MkdirAll(o.dir, 0o755); err != nil {
+		return err
+	}
+
+	name := fmt.Sprintf("%s-%s.eml", time.Now().UTC().Format("20060102T150405"), uuid.NewString())
+	return os.WriteFile(filepath.Join(o.dir, name), []byte(content), 0o600)
+}
@@ -0,0 +1,120 @@
+// Package password hashes passwords with Argon2id. Hashes are stored in the
+// PHC string format, which records the algorithm and its parameters, so
+// older hashes (including bcrypt ones) keep verifying after the parameters
+// change and can be upgraded on the next successful login.
+package password
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+	"strings"
+)
+
+var ErrUnknownHash = errors.New("unknown password hash format")
+
+const (
+	saltLength = 16
+	keyLength  = 32
+)
+
+type Config struct {
+	// Memory is given in KiB.
+	Memory      uint32
+	Iterations  uint32
+	Parallelism uint8
+
+	MinLength    int
+	RejectCommon bool
+}
+
+type Hasher struct {
+	cfg   Config
+	dummy string
+}
+
+func NewHasher(cfg Config) *Hasher {
+	h := &Hasher{cfg: cfg}
+	h.dummy, _ = h.Hash("dummy password for timing")
+	return h
+}
+
+func (h *Hasher) Hash(password string) (string, error) {
+	salt := make([]byte, saltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+
+	key := argon2.IDKey([]byte(password), salt, h.cfg.Iterations, h.cfg.Memory, h.cfg.Parallelism, keyLength)
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version, h.cfg.Memory, h.cfg.Iterations, h.cfg.Parallelism,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+// Verify checks password against hash. rehash reports that the password
+// matched but hash was made with another algorithm or other parameters and
+// should be replaced by Hash(password).
+func (h *Hasher) Verify(password, hash string) (ok bool, rehash bool, err error) {
+	switch {
+	case strings.HasPrefix(hash, "$argon2id$"):
+		return h.verifyArgon2id(password, hash)
+	case strings.HasPrefix(hash, "$2a$"), strings.HasPrefix(hash, "$2b$"), strings.HasPrefix(hash, "$2y$"):
+		err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
+		if err != nil {
+			if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+				return false, false, nil
+			}
+			return false, false, err
+		}
+		return true, true, nil
+	default:
+		return false, false, ErrUnknownHash
+	}
+}
+
+// VerifyDummy is used when a login names an account that does not exist or
+// cannot log in, so those requests take as long as a wrong password.
+func (h *Hasher) VerifyDummy(password string) {
+	_, _, _ = h.Verify(password, h.dummy)
+}
+
+func (h *Hasher) verifyArgon2id(password, hash string) (bool, bool, error) {
+	parts := strings.Split(hash, "$")
+	if len(parts) != 6 {
+		return false, false, ErrUnknownHash
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return false, false, ErrUnknownHash
+	}
+
+	var memory, iterations uint32
+	var parallelism uint8
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &memory, &iterations, &parallelism); err != nil {
+		return false, false, ErrUnknownHash
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return false, false, ErrUnknownHash
+	}
+	want, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil {
+		return false, false, ErrUnknownHash
+	}
+
+	got := argon2.IDKey([]byte(password), salt, iterations, memory, parallelism, uint32(len(want)))
+	if subtle.ConstantTimeCompare(got, want) != 1 {
+		return false, false, nil
+	}
+
+	rehash := memory != h.cfg.Memory || iterations != h.cfg.Iterations || parallelism != h.cfg.Parallelism
+	return true, rehash, nil
+}
@@ -0,0 +1,121 @@
+package password
+
+import (
+	"errors"
+	"golang.org/x/crypto/bcrypt"
+	"strings"
+	"testing"
+)
+
+// testConfig keeps Argon2id cheap so the tests run fast.
+var testConfig = Config{Memory: 64, Iterations: 1, Parallelism: 1, MinLength: 8, RejectCommon: true}
+
+func TestHashFormat(t *testing.T) {
+	h := NewHasher(testConfig)
+
+	hash, err := h.Hash("correct horse battery staple")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parts := strings.Split(hash, "$")
+	if len(parts) != 6 || parts[1] != "argon2id" || parts[2] != "v=19" || parts[3] != "m=64,t=1,p=1" {
+		t.Fatalf("hash %q is not a PHC argon2id string with the configured parameters", hash)
+	}
+
+	other, err := h.Hash("correct horse battery staple")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hash == other {
+		t.Error("hashes of the same password are equal, the salt is not random")
+	}
+}
+
+func TestVerify(t *testing.T) {
+	h := NewHasher(testConfig)
+	hash, err := h.Hash("correct horse battery staple")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stronger := testConfig
+	stronger.Iterations = 2
+	bcryptHash, err := bcrypt.GenerateFromPassword([]byte("correct horse battery staple"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		hasher     *Hasher
+		password   string
+		hash       string
+		wantOK     bool
+		wantRehash bool
+	}{
+		{"argon2id", h, "correct horse battery staple", hash, true, false},
+		{"argon2id wrong password", h, "wrong password", hash, false, false},
+		{"argon2id other parameters", NewHasher(stronger), "correct horse battery staple", hash, true, true},
+		{"bcrypt", h, "correct horse battery staple", string(bcryptHash), true, true},
+		{"bcrypt wrong password", h, "wrong password", string(bcryptHash), false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ok, rehash, err := tt.hasher.Verify(tt.password, tt.hash)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if ok != tt.wantOK || rehash != tt.wantRehash {
+				t.Errorf("Verify = %v, %v, want %v, %v", ok, rehash, tt.wantOK, tt.wantRehash)
+			}
+		})
+	}
+}
+
+func TestVerifyUnknownHash(t *testing.T) {
+	h := NewHasher(testConfig)
+
+	hashes := []string{
+		"",
+		"plain text",
+		"$argon2i$v=19$m=64,t=1,p=1$c2FsdA$a2V5",
+		"$argon2id$v=16$m=64,t=1,p=1$c2FsdA$a2V5",
+		"$argon2id$v=19$m=64,t=1$c2FsdA$a2V5",
+		"$argon2id$v=19$m=64,t=1,p=1$not base64!$a2V5",
+		"$argon2id$v=19$m=64,t=1,p=1$c2FsdA",
+	}
+	for _, hash := range hashes {
+		if _, _, err := h.Verify("password", hash); !errors.Is(err, ErrUnknownHash) {
+			t.Errorf("Verify with %q: got %v, want ErrUnknownHash", hash, err)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	h := NewHasher(testConfig)
+	allowCommon := testConfig
+	allowCommon.RejectCommon = false
+
+	tests := []struct {
+		name     string
+		hasher   *Hasher
+		password string
+		wantErr  bool
+	}{
+		{"long enough", h, "correct horse battery staple", false},
+		{"too short", h, "short", true},
+		{"length counts characters, not bytes", h, "пароль", true},
+		{"too long", h, strings.Repeat("a", MaxLength+1), true},
+		{"common", h, "password", true},
+		{"common in another case", h, "PassWord", true},
+		{"common allowed", NewHasher(allowCommon), "password", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.hasher.Validate(tt.password); (err != nil) != tt.wantErr {
+				t.Errorf("Validate(%q) = %v, want error: %v", tt.password, err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package password
+
+import (
+	"bufio"
+	_ "embed"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// MaxLength bounds the work a single login can cause.
+const MaxLength = 256
+
+var ErrCommonPassword = errors.New("password is too common")
+
+//go:embed common.txt
+var commonList string
+
+var common = func() map[string]bool {
+	set := make(map[string]bool)
+	scanner := bufio.NewScanner(strings.NewReader(commonList))
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" && !strings.HasPrefix(line, "#") {
+			set[strings.ToLower(line)] = true
+		}
+	}
+	return set
+}()
+
+// Validate checks a new password against the policy. Existing passwords are
+// never validated, so tightening the policy does not lock anyone out.
+func (h *Hasher) Validate(password string) error {
+	length := utf8.RuneCountInString(password)
+	if length < h.cfg.MinLength {
+		return fmt.Errorf("password must be at least %d characters long", h.cfg.MinLength)
+	}
+	if length > MaxLength {
+		return fmt.Errorf("password must be at most %d characters long", MaxLength)
+	}
+	if h.cfg.RejectCommon && common[strings.ToLower(password)] {
+		return ErrCommonPassword
+	}
+	return nil
+}
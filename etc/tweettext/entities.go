@@ -0,0 +1,193 @@
+package tweettext
+
+import (
+	"golang.org/x/text/unicode/norm"
+	"strings"
+	"unicode"
+)
+
+// Entities are the hashtags, mentions, cashtags and URLs of a tweet. Start
+// and End are code point offsets; End is exclusive.
+type Entities struct {
+	Hashtags []Hashtag `json:"hashtags,omitempty"`
+	Mentions []Mention `json:"mentions,omitempty"`
+	Cashtags []Cashtag `json:"cashtags,omitempty"`
+	URLs     []URL     `json:"urls,omitempty"`
+}
+
+type Hashtag struct {
+	Start int    `json:"start"`
+	End   int    `json:"end"`
+	Tag   string `json:"tag"`
+}
+
+// Mention is an @username. UserID is filled in once the username has been
+// resolved to an account.
+type Mention struct {
+	Start    int    `json:"start"`
+	End      int    `json:"end"`
+	Username string `json:"username"`
+	UserID   string `json:"user_id,omitempty"`
+}
+
+type Cashtag struct {
+	Start int    `json:"start"`
+	End   int    `json:"end"`
+	Tag   string `json:"tag"`
+}
+
+type URL struct {
+	Start int    `json:"start"`
+	End   int    `json:"end"`
+	URL   string `json:"url"`
+}
+
+// MaxUsernameLength is the longest username a mention can name.
+const MaxUsernameLength = 15
+
+// Extract finds the entities of normalized text. Nothing inside a URL is
+// taken for another entity.
+func Extract(text string) Entities {
+	runes := []rune(text)
+	entities := Entities{URLs: extractURLs(runes)}
+
+	for i := 0; i < len(runes); i++ {
+		if url, ok := urlAt(entities.URLs, i); ok {
+			i = url.End - 1
+			continue
+		}
+
+		var prev rune
+		if i > 0 {
+			prev = runes[i-1]
+		}
+
+		switch runes[i] {
+		case '#', '＃':
+			if isWordRune(prev) || prev == '&' {
+				continue
+			}
+			end := scan(runes, i+1, isWordRune)
+			tag := string(runes[i+1 : end])
+			if strings.IndexFunc(tag, func(r rune) bool { return !unicode.IsDigit(r) && r != '_' }) < 0 {
+				continue
+			}
+			entities.Hashtags = append(entities.Hashtags, Hashtag{Start: i, End: end, Tag: tag})
+			i = end - 1
+
+		case '@', '＠':
+			if isWordRune(prev) || strings.ContainsRune("!@#$%&*＠", prev) {
+				continue
+			}
+			end := scan(runes, i+1, isUsernameRune)
+			if end == i+1 || end-i-1 > MaxUsernameLength || (end < len(runes) && (runes[end] == '@' || isWordRune(runes[end]))) {
+				i = end - 1
+				continue
+			}
+			entities.Mentions = append(entities.Mentions, Mention{Start: i, End: end, Username: string(runes[i+1 : end])})
+			i = end - 1
+
+		case '$':
+			if prev != 0 && !unicode.IsSpace(prev) {
+				continue
+			}
+			end := scan(runes, i+1, isASCIILetter)
+			if end == i+1 || end-i-1 > 6 {
+				continue
+			}
+			if end+1 < len(runes) && (runes[end] == '.' || runes[end] == '_') && isASCIILetter(runes[end+1]) {
+				suffix := scan(runes, end+1, isASCIILetter)
+				if suffix-end-1 <= 2 {
+					end = suffix
+				}
+			}
+			if end < len(runes) && isWordRune(runes[end]) {
+				continue
+			}
+			entities.Cashtags = append(entities.Cashtags, Cashtag{Start: i, End: end, Tag: string(runes[i+1 : end])})
+			i = end - 1
+		}
+	}
+	return entities
+}
+
+// extractURLs finds links starting with http://, https:// or www. and
+// leaves out trailing punctuation and unbalanced closing parentheses.
+func extractURLs(runes []rune) []URL {
+	var urls []URL
+	for i := 0; i < len(runes); i++ {
+		if i > 0 && (isWordRune(runes[i-1]) || runes[i-1] == '/' || runes[i-1] == '.') {
+			continue
+		}
+
+		rest := strings.ToLower(string(runes[i:min(i+8, len(runes))]))
+		var prefix int
+		switch {
+		case strings.HasPrefix(rest, "https://"):
+			prefix = 8
+		case strings.HasPrefix(rest, "http://"):
+			prefix = 7
+		case strings.HasPrefix(rest, "www."):
+			prefix = 4
+		default:
+			continue
+		}
+
+		end := scan(runes, i, func(r rune) bool { return !unicode.IsSpace(r) })
+		end = trimURL(runes, i, end)
+
+		host := string(runes[i+prefix : end])
+		if slash := strings.IndexAny(host, "/?#"); slash >= 0 {
+			host = host[:slash]
+		}
+		if !strings.Contains(strings.Trim(host, "."), ".") && !(prefix == 4 && host != "") {
+			continue
+		}
+
+		urls = append(urls, URL{Start: i, End: end, URL: string(runes[i:end])})
+		i = end - 1
+	}
+	return urls
+}
+
+func trimURL(runes []rune, start, end int) int {
+	for end > start {
+		last := runes[end-1]
+		if strings.ContainsRune(".,;:!?'\"", last) {
+			end--
+			continue
+		}
+		if last == ')' && strings.Count(string(runes[start:end]), "(") < strings.Count(string(runes[start:end]), ")") {
+			end--
+			continue
+		}
+		break
+	}
+	return end
+}
+
+func scan(runes []rune, from int, match func(rune) bool) int {
+	end := from
+	for end < len(runes) && match(runes[end]) {
+		end++
+	}
+	return end
+}
+
+func isWordRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.IsMark(r)
+}
+
+func isUsernameRune(r rune) bool {
+	return r == '_' || r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r))
+}
+
+func isASCIILetter(r rune) bool {
+	return r < unicode.MaxASCII && unicode.IsLetter(r)
+}
+
+// NormalizeHashtag returns the form hashtags are indexed and looked up by,
+// so #Go, #GO and #ｇｏ are the same hashtag.
+func NormalizeHashtag(tag string) string {
+	return strings.ToLower(norm.NFKC.String(strings.TrimLeft(tag, "#＃")))
+}
@@ -0,0 +1,92 @@
+package tweettext
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExtract(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want Entities
+	}{
+		{
+			name: "plain text",
+			text: "nothing to see here",
+		},
+		{
+			name: "hashtags",
+			text: "#go and #Go2",
+			want: Entities{Hashtags: []Hashtag{{0, 3, "go"}, {8, 12, "Go2"}}},
+		},
+		{
+			name: "fullwidth hash",
+			text: "＃日本",
+			want: Entities{Hashtags: []Hashtag{{0, 3, "日本"}}},
+		},
+		{
+			name: "no hashtag of digits or inside a word",
+			text: "#123 a#b &#39",
+		},
+		{
+			name: "mentions",
+			text: "@alice, hi @Bob_2",
+			want: Entities{Mentions: []Mention{{Start: 0, End: 6, Username: "alice"}, {Start: 11, End: 17, Username: "Bob_2"}}},
+		},
+		{
+			name: "no mention in an address or past the username length",
+			text: "mail@example.com @abcdefghijklmnop @a@b",
+		},
+		{
+			name: "cashtags",
+			text: "$AAPL and $BRK.B",
+			want: Entities{Cashtags: []Cashtag{{0, 5, "AAPL"}, {10, 16, "BRK.B"}}},
+		},
+		{
+			name: "no cashtag of an amount or inside a word",
+			text: "costs $5 or a$B",
+		},
+		{
+			name: "url hides what is inside it",
+			text: "https://example.com/#tag @x",
+			want: Entities{
+				Mentions: []Mention{{Start: 25, End: 27, Username: "x"}},
+				URLs:     []URL{{0, 24, "https://example.com/#tag"}},
+			},
+		},
+		{
+			name: "url without trailing punctuation or unbalanced parenthesis",
+			text: "(see www.example.com/a_(b)).",
+			want: Entities{URLs: []URL{{5, 26, "www.example.com/a_(b)"}}},
+		},
+		{
+			name: "no url without a dot in the host",
+			text: "http://localhost",
+		},
+	}
+	for _, tt := range tests {
+		if got := Extract(tt.text); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: Extract(%q) = %+v, want %+v", tt.name, tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestNormalizeHashtag(t *testing.T) {
+	tests := []struct {
+		tag  string
+		want string
+	}{
+		{"go", "go"},
+		{"#Go", "go"},
+		{"＃GO", "go"},
+		{"ｇｏ", "go"},
+		{"Straße", "straße"},
+		{"日本", "日本"},
+	}
+	for _, tt := range tests {
+		if got := NormalizeHashtag(tt.tag); got != tt.want {
+			t.Errorf("NormalizeHashtag(%q) = %q, want %q", tt.tag, got, tt.want)
+		}
+	}
+}
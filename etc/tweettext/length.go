@@ -0,0 +1,100 @@
+// Package tweettext counts and parses tweet text the way twitter-text does.
+// Offsets are code point offsets into the NFC normalized text.
+package tweettext
+
+import (
+	"errors"
+	"fmt"
+	"github.com/rivo/uniseg"
+	"golang.org/x/text/unicode/norm"
+	"strings"
+)
+
+// URLLength is what every URL counts as, however long it is.
+const URLLength = 23
+
+// lightRanges are the code points that count as one character. Everything
+// else, such as CJK characters, counts as two, and so does every emoji
+// sequence.
+var lightRanges = [][2]rune{
+	{0, 4351},
+	{8192, 8205},
+	{8208, 8223},
+	{8242, 8247},
+}
+
+var ErrInvalidCharacter = errors.New("text contains invalid characters")
+
+// Normalize returns the text in the form it is stored, counted and parsed in.
+func Normalize(text string) string {
+	return norm.NFC.String(text)
+}
+
+// Length returns the weighted length of normalized text.
+func Length(text string) int {
+	var (
+		length int
+		offset int
+		urls   = extractURLs([]rune(text))
+	)
+
+	graphemes := uniseg.NewGraphemes(text)
+	for graphemes.Next() {
+		runes := graphemes.Runes()
+		start := offset
+		offset += len(runes)
+
+		if url, ok := urlAt(urls, start); ok {
+			if start == url.Start {
+				length += URLLength
+			}
+			continue
+		}
+
+		if len(runes) > 1 && graphemes.Width() == 2 {
+			length += 2
+			continue
+		}
+		for _, r := range runes {
+			length += weight(r)
+		}
+	}
+	return length
+}
+
+// Validate checks normalized text against the maximum weighted length.
+func Validate(text string, maxLength int) error {
+	if strings.ContainsFunc(text, invalid) {
+		return ErrInvalidCharacter
+	}
+	if length := Length(text); length > maxLength {
+		return fmt.Errorf("text is %d characters long, the maximum is %d", length, maxLength)
+	}
+	return nil
+}
+
+func weight(r rune) int {
+	for _, light := range lightRanges {
+		if r >= light[0] && r <= light[1] {
+			return 1
+		}
+	}
+	return 2
+}
+
+func invalid(r rune) bool {
+	switch r {
+	case '\uFFFE', '\uFEFF', '\uFFFF':
+		return true
+	}
+	return r >= '\u202A' && r <= '\u202E'
+}
+
+func urlAt(urls []URL, offset int) (URL, bool) {
+	for _, url := range urls {
+		if offset >= url.Start && offset < url.End {
+			return url, true
+		}
+	}
+	return URL{}, false
+}
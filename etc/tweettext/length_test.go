@@ -0,0 +1,61 @@
+package tweettext
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestLength(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want int
+	}{
+		{"empty", "", 0},
+		{"latin", "hello", 5},
+		{"combining mark after normalizing", Normalize("e\u0301"), 1},
+		{"cjk counts two", "日本語", 6},
+		{"symbol outside the light ranges", "café ☕", 7},
+		{"emoji", "😀", 2},
+		{"emoji with skin tone", "👍🏽", 2},
+		{"zwj sequence", "👨‍👩‍👧", 2},
+		{"url", "https://example.com/a/very/long/path/that/goes/on", URLLength},
+		{"short url", "www.go.dev", URLLength},
+		{"url in text", "see https://example.com.", 4 + URLLength + 1},
+		{"two urls", "http://a.io http://b.io", 2*URLLength + 1},
+	}
+	for _, tt := range tests {
+		if got := Length(tt.text); got != tt.want {
+			t.Errorf("%s: Length(%q) = %d, want %d", tt.name, tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		max     int
+		wantErr bool
+	}{
+		{"within the limit", "hello", 5, false},
+		{"over the limit", "hello!", 5, true},
+		{"cjk over the limit", "日本語", 5, true},
+		{"long url within the limit", "https://example.com/" + strings.Repeat("a", 100), URLLength, false},
+		{"byte order mark", "hi\uFEFF", 280, true},
+		{"direction override", "hi\u202Eih", 280, true},
+	}
+	for _, tt := range tests {
+		err := Validate(tt.text, tt.max)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: Validate(%q, %d) = %v, want error %v", tt.name, tt.text, tt.max, err, tt.wantErr)
+		}
+	}
+}
+
+func TestValidateInvalidCharacter(t *testing.T) {
+	if err := Validate("a\uFFFF", 280); !errors.Is(err, ErrInvalidCharacter) {
+		t.Errorf("Validate = %v, want ErrInvalidCharacter", err)
+	}
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"github.com/joho/godotenv"
 	"gorm.io/driver/postgres"
@@ -9,8 +10,14 @@ import (
 	"os"
 	"project/api"
 	"project/api/controllers"
+	"project/api/middleware"
+	"project/config"
 	"project/database"
+	"project/database/cache"
+	auth "project/etc/jwt"
+	"project/etc/mailer"
 	"project/models"
+	"project/worker"
 	"time"
 )
 
@@ -43,6 +50,10 @@ func setupDatabase() (*gorm.DB, error) {
 		log.Fatalf("Failed to auto migrate %v", err)
 	}
 
+	if err := models.Seed(db, os.Getenv("ADMIN_USERNAME")); err != nil {
+		log.Fatalf("Failed to seed database %v", err)
+	}
+
 	return db, nil
 }
 
@@ -56,10 +67,23 @@ func main() {
 		log.Fatalf("Failed to setup database %v", err)
 	}
 
+	cfg := config.Load()
 	store := database.New(db)
-	cont := controllers.NewController(store)
+	kv := cache.New(cfg.RedisURL)
+	revoker := auth.NewRevoker(kv, cfg.AccessTokenTTL)
+	keys, err := auth.NewKeyManager(store.SigningKey(), cfg.JWT)
+	if err != nil {
+		log.Fatalf("Failed to load signing keys %v", err)
+	}
+	go keys.Run(context.Background())
+	go worker.NewPurger(store, cfg).Run(context.Background())
+	go worker.NewTrender(store, cfg).Run(context.Background())
+	go worker.NewPublisher(store, cfg).Run(context.Background())
+
+	cont := controllers.NewController(store, cfg, kv, revoker, keys, mailer.New(cfg.Mail))
+	mw := middleware.New(store, cfg, revoker, keys)
 
-	router := api.Construct(*cont)
+	router := api.Construct(*cont, mw)
 
 	if err := router.Run(":8080"); err != nil {
 		log.Fatalf("Failed to start server: %v", err)
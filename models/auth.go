@@ -1,10 +1,39 @@
 package models
 
 type LoginRequest struct {
+	// Username accepts either the username or the e-mail address.
 	Username string `json:"username" binding:"required"`
 	Password string `json:"password" binding:"required"`
 }
 
+// LoginResponse carries either a token pair or, when a second factor is
+// needed, a short-lived challenge or enrollment token.
 type LoginResponse struct {
-	Token string `json:"token"`
+	Token        string `json:"token,omitempty"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+	ExpiresIn    int64  `json:"expires_in,omitempty"`
+
+	TwoFactorRequired bool   `json:"two_factor_required,omitempty"`
+	ChallengeToken    string `json:"challenge_token,omitempty"`
+
+	TwoFactorEnrollmentRequired bool   `json:"two_factor_enrollment_required,omitempty"`
+	EnrollmentToken             string `json:"enrollment_token,omitempty"`
+
+	// Reactivated is set when the login undid a pending account deletion.
+	Reactivated bool `json:"reactivated,omitempty"`
+}
+
+type ChangePasswordRequest struct {
+	OldPassword string `json:"old_password" binding:"required"`
+	NewPassword string `json:"new_password" binding:"required"`
+}
+
+type ForgotPasswordRequest struct {
+	// Username accepts either the username or the e-mail address.
+	Username string `json:"username" binding:"required"`
+}
+
+type ResetPasswordRequest struct {
+	Token       string `json:"token" binding:"required"`
+	NewPassword string `json:"new_password" binding:"required"`
 }
@@ -0,0 +1,24 @@
+package models
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+// Block hides two users from each other in both directions and keeps them
+// from following one another.
+type Block struct {
+	ID        uuid.UUID `gorm:"type:uuid;primaryKey"`
+	BlockerID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_blocks_pair"`
+	BlockedID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_blocks_pair;index"`
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+}
+
+// Mute only hides the muted user from the timelines of the muter. The muted
+// user is not told and can still see and follow the muter.
+type Mute struct {
+	ID        uuid.UUID `gorm:"type:uuid;primaryKey"`
+	MuterID   uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_mutes_pair"`
+	MutedID   uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_mutes_pair"`
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+}
@@ -0,0 +1,42 @@
+package models
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+// Draft is an unfinished tweet, visible to its author only. A draft that
+// sets InReplyToID is published as a reply and one that sets QuotedTweetID
+// as a quote.
+type Draft struct {
+	Id            uuid.UUID   `gorm:"primary_key; type:uuid" json:"id"`
+	UserID        uuid.UUID   `gorm:"type:uuid; not null; index" json:"user_id"`
+	Content       string      `gorm:"type:text; not null" json:"content"`
+	ImagePath     *string     `gorm:"size:255" json:"image_path"`
+	VideoPath     *string     `gorm:"size:255" json:"video_path"`
+	InReplyToID   *uuid.UUID  `gorm:"type:uuid" json:"in_reply_to_id"`
+	QuotedTweetID *uuid.UUID  `gorm:"type:uuid" json:"quoted_tweet_id"`
+	Poll          *CreatePoll `gorm:"type:jsonb; serializer:json" json:"poll"`
+	CreatedAt     time.Time   `json:"created_at"`
+	UpdatedAt     time.Time   `json:"updated_at"`
+}
+
+type CreateUpdateDraft struct {
+	Content       string      `json:"content"`
+	ImagePath     *string     `json:"image_path"`
+	VideoPath     *string     `json:"video_path"`
+	InReplyToID   *uuid.UUID  `json:"in_reply_to_id"`
+	QuotedTweetID *uuid.UUID  `json:"quoted_tweet_id"`
+	Poll          *CreatePoll `json:"poll"`
+}
+
+type GetDraftsRequest struct {
+	UserID uuid.UUID
+	Page   uint64
+	Limit  uint64
+}
+
+type GetDraftsResponse struct {
+	Drafts []Draft `json:"drafts"`
+	Count  int64   `json:"count"`
+}
@@ -0,0 +1,37 @@
+package models
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+// Hashtag is a normalized hashtag, see tweettext.NormalizeHashtag.
+type Hashtag struct {
+	Id        uuid.UUID `gorm:"primary_key; type:uuid"`
+	Tag       string    `gorm:"size:255; not null; uniqueIndex"`
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+}
+
+// TweetHashtag links a tweet to a hashtag it uses. CreatedAt is the time of
+// the tweet, which hashtag timelines and trends are ordered by.
+type TweetHashtag struct {
+	TweetID   uuid.UUID `gorm:"primary_key; type:uuid"`
+	HashtagID uuid.UUID `gorm:"primary_key; type:uuid; index:idx_tweet_hashtags_recent,priority:1"`
+	CreatedAt time.Time `gorm:"not null; index:idx_tweet_hashtags_recent,priority:2; index"`
+}
+
+// Trend is a hashtag ranked by its recent usage. Every use counts less the
+// older it is, halving every half-life.
+type Trend struct {
+	HashtagID  uuid.UUID `gorm:"primary_key; type:uuid" json:"-"`
+	Tag        string    `gorm:"size:255; not null" json:"tag"`
+	Rank       int       `gorm:"not null; index" json:"rank"`
+	Score      float64   `gorm:"not null" json:"score"`
+	TweetCount int64     `gorm:"not null" json:"tweet_count"`
+	ComputedAt time.Time `gorm:"not null" json:"computed_at"`
+}
+
+type GetTrendsResponse struct {
+	Trends []Trend `json:"trends"`
+	Count  int     `json:"count"`
+}
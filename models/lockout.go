@@ -0,0 +1,28 @@
+package models
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+// LoginLockout records every time an account was locked after too many
+// failed logins, so admins can spot accounts under attack.
+type LoginLockout struct {
+	Id             uuid.UUID `gorm:"primary_key; type:uuid"`
+	UserID         uuid.UUID `gorm:"type:uuid; not null; index"`
+	IP             string    `gorm:"size:64; not null"`
+	FailedAttempts int       `gorm:"not null"`
+	LockedUntil    time.Time `gorm:"not null"`
+	CreatedAt      time.Time
+}
+
+type GetAllLockoutsRequest struct {
+	Page   uint64    `json:"page"`
+	Limit  uint64    `json:"limit"`
+	UserID uuid.UUID `json:"user_id"`
+}
+
+type GetAllLockoutsResponse struct {
+	Lockouts []LoginLockout `json:"lockouts"`
+	Count    int64          `json:"count"`
+}
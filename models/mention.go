@@ -0,0 +1,14 @@
+package models
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+// Mention links a tweet to a user it mentions. It refers to the user by id,
+// so it survives renames. CreatedAt is the time of the tweet.
+type Mention struct {
+	TweetID   uuid.UUID `gorm:"primary_key; type:uuid"`
+	UserID    uuid.UUID `gorm:"primary_key; type:uuid; index:idx_mentions_recent,priority:1"`
+	CreatedAt time.Time `gorm:"not null; index:idx_mentions_recent,priority:2"`
+}
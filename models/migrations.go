@@ -1,12 +1,343 @@
 package models
 
-import "gorm.io/gorm"
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"project/etc/tweettext"
+	"strings"
+)
 
 func AutoMigrate(db *gorm.DB) error {
-	return db.AutoMigrate(
+	hadConversations := db.Migrator().HasColumn(&Tweet{}, "conversation_id")
+	hadKinds := db.Migrator().HasColumn(&Tweet{}, "kind")
+	hadEntities := db.Migrator().HasColumn(&Tweet{}, "entities")
+	hadHashtags := db.Migrator().HasTable(&TweetHashtag{})
+	hadMentions := db.Migrator().HasTable(&Mention{})
+
+	err := db.AutoMigrate(
 		&User{},
 		&Tweet{},
+		&TweetRevision{},
+		&Hashtag{},
+		&TweetHashtag{},
+		&Mention{},
+		&ScheduledTweet{},
+		&Draft{},
+		&Poll{},
+		&PollOption{},
+		&PollVote{},
+		&Trend{},
 		&Follow{},
 		&Like{},
+		&Block{},
+		&Mute{},
+		&RefreshToken{},
+		&UserToken{},
+		&RecoveryCode{},
+		&LoginLockout{},
+		&PersonalAccessToken{},
+		&SigningKey{},
+		&Session{},
+		&LoginEvent{},
+		&Permission{},
+		&Role{},
+		&UserRole{},
 	)
+	if err != nil {
+		return err
+	}
+
+	// Every tweet written before replies existed starts its own conversation.
+	if !hadConversations {
+		if err := db.Exec("UPDATE tweets SET conversation_id = id WHERE conversation_id IS NULL").Error; err != nil {
+			return err
+		}
+	}
+
+	if !hadKinds {
+		if err := migrateTweetKinds(db); err != nil {
+			return err
+		}
+	}
+
+	if !db.Migrator().HasIndex(&Tweet{}, RetweetIndex) {
+		if err := migrateRetweetIndex(db); err != nil {
+			return err
+		}
+	}
+
+	if !hadEntities {
+		if err := migrateTweetEntities(db); err != nil {
+			return err
+		}
+	}
+
+	if !hadHashtags {
+		if err := migrateHashtags(db); err != nil {
+			return err
+		}
+	}
+
+	if !hadMentions {
+		return migrateMentions(db)
+	}
+	return nil
+}
+
+// migrateMentions resolves the mentions of tweets written before mentions
+// were linked to users. Mentions of usernames without an active account
+// are dropped from the entities.
+func migrateMentions(db *gorm.DB) error {
+	var tweets []Tweet
+	return db.Unscoped().Select("id", "entities", "created_at").Where("entities -> 'mentions' IS NOT NULL").
+		FindInBatches(&tweets, 500, func(_ *gorm.DB, _ int) error {
+			var usernames []string
+			for _, tweet := range tweets {
+				for _, mention := range tweet.Entities.Mentions {
+					usernames = append(usernames, strings.ToLower(mention.Username))
+				}
+			}
+
+			var users []User
+			err := db.Select("id", "username").
+				Where("LOWER(username) IN ? AND deactivated_at IS NULL", usernames).
+				Find(&users).Error
+			if err != nil {
+				return err
+			}
+			// Usernames match case-insensitively, like new mentions do; an
+			// exact match wins over one differing in case.
+			ids := make(map[string]uuid.UUID, len(users))
+			for _, user := range users {
+				ids[user.Username] = user.Id
+			}
+			folded := make(map[string]uuid.UUID, len(users))
+			for _, user := range users {
+				if _, ok := folded[strings.ToLower(user.Username)]; !ok {
+					folded[strings.ToLower(user.Username)] = user.Id
+				}
+			}
+
+			var links []Mention
+			for _, tweet := range tweets {
+				entities := *tweet.Entities
+				entities.Mentions = nil
+				for _, mention := range tweet.Entities.Mentions {
+					id, ok := ids[mention.Username]
+					if !ok {
+						id, ok = folded[strings.ToLower(mention.Username)]
+					}
+					if !ok {
+						continue
+					}
+					mention.UserID = id.String()
+					entities.Mentions = append(entities.Mentions, mention)
+					links = append(links, Mention{TweetID: tweet.Id, UserID: id, CreatedAt: tweet.CreatedAt})
+				}
+
+				err := db.Model(&Tweet{}).Unscoped().
+					Where("id = ?", tweet.Id).
+					Select("Entities").
+					Updates(&Tweet{Entities: &entities}).Error
+				if err != nil {
+					return err
+				}
+			}
+			if len(links) == 0 {
+				return nil
+			}
+			return db.Clauses(clause.OnConflict{DoNothing: true}).Create(&links).Error
+		}).Error
+}
+
+// migrateHashtags links tweets written before hashtags were indexed to the
+// hashtags in their entities.
+func migrateHashtags(db *gorm.DB) error {
+	var tweets []Tweet
+	return db.Select("id", "entities", "created_at").Where("entities -> 'hashtags' IS NOT NULL").
+		FindInBatches(&tweets, 500, func(_ *gorm.DB, _ int) error {
+			tags := make(map[uuid.UUID][]string)
+			var hashtags []Hashtag
+			seen := make(map[string]bool)
+			for _, tweet := range tweets {
+				linked := make(map[string]bool)
+				for _, entity := range tweet.Entities.Hashtags {
+					tag := tweettext.NormalizeHashtag(entity.Tag)
+					if tag == "" || linked[tag] {
+						continue
+					}
+					linked[tag] = true
+					tags[tweet.Id] = append(tags[tweet.Id], tag)
+					if !seen[tag] {
+						seen[tag] = true
+						hashtags = append(hashtags, Hashtag{Id: uuid.New(), Tag: tag})
+					}
+				}
+			}
+			if len(hashtags) == 0 {
+				return nil
+			}
+
+			err := db.Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "tag"}}, DoNothing: true}).
+				Create(&hashtags).Error
+			if err != nil {
+				return err
+			}
+
+			var stored []Hashtag
+			if err := db.Where("tag IN ?", hashtagTags(hashtags)).Find(&stored).Error; err != nil {
+				return err
+			}
+			ids := make(map[string]uuid.UUID, len(stored))
+			for _, hashtag := range stored {
+				ids[hashtag.Tag] = hashtag.Id
+			}
+
+			var links []TweetHashtag
+			for _, tweet := range tweets {
+				for _, tag := range tags[tweet.Id] {
+					links = append(links, TweetHashtag{TweetID: tweet.Id, HashtagID: ids[tag], CreatedAt: tweet.CreatedAt})
+				}
+			}
+			return db.Clauses(clause.OnConflict{DoNothing: true}).Create(&links).Error
+		}).Error
+}
+
+func hashtagTags(hashtags []Hashtag) []string {
+	tags := make([]string, len(hashtags))
+	for i, hashtag := range hashtags {
+		tags[i] = hashtag.Tag
+	}
+	return tags
+}
+
+// migrateTweetEntities extracts the entities of tweets written before they
+// were stored. Their content is left as it was, so offsets of text that is
+// not in normal form may be off.
+func migrateTweetEntities(db *gorm.DB) error {
+	var tweets []Tweet
+	return db.Unscoped().Select("id", "content").Where("content <> ''").
+		FindInBatches(&tweets, 500, func(_ *gorm.DB, _ int) error {
+			for _, tweet := range tweets {
+				entities := tweettext.Extract(tweet.Content)
+				err := db.Model(&Tweet{}).Unscoped().
+					Where("id = ?", tweet.Id).
+					Select("Entities").
+					Updates(&Tweet{Entities: &entities}).Error
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		}).Error
+}
+
+// RetweetIndex keeps a user from retweeting the same tweet twice.
+const RetweetIndex = "idx_tweets_user_retweet"
+
+// migrateRetweetIndex points retweets of retweets at the original tweet,
+// removes the duplicate retweets that were allowed before and adds the
+// index that prevents new ones.
+func migrateRetweetIndex(db *gorm.DB) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		statements := []string{
+			`UPDATE tweets SET retweet_id = shared.retweet_id
+				FROM tweets AS shared
+				WHERE tweets.retweet_id = shared.id AND shared.retweet_id IS NOT NULL`,
+			`UPDATE tweets SET deleted_at = NOW()
+				WHERE id IN (SELECT id FROM (
+					SELECT id, ROW_NUMBER() OVER (PARTITION BY user_id, retweet_id ORDER BY created_at) AS n
+					FROM tweets WHERE retweet_id IS NOT NULL AND deleted_at IS NULL) AS retweets
+				WHERE n > 1)`,
+			`UPDATE tweets SET retweet_count = (SELECT COUNT(*) FROM tweets AS retweets
+				WHERE retweets.retweet_id = tweets.id AND retweets.deleted_at IS NULL)`,
+			`CREATE UNIQUE INDEX ` + RetweetIndex + ` ON tweets (user_id, retweet_id)
+				WHERE retweet_id IS NOT NULL AND deleted_at IS NULL`,
+		}
+		for _, statement := range statements {
+			if err := tx.Exec(statement).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// migrateTweetKinds classifies tweets written before kinds existed. A tweet
+// that shared another one with content of its own was a quote all along.
+func migrateTweetKinds(db *gorm.DB) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		statements := []string{
+			`UPDATE tweets SET kind = 'reply' WHERE in_reply_to_id IS NOT NULL`,
+			`UPDATE tweets SET kind = 'quote', quoted_tweet_id = retweet_id, retweet_id = NULL
+				WHERE retweet_id IS NOT NULL AND content <> ''`,
+			`UPDATE tweets SET kind = 'retweet' WHERE retweet_id IS NOT NULL`,
+			`UPDATE tweets SET retweet_count = shared.n
+				FROM (SELECT retweet_id, COUNT(*) AS n FROM tweets
+					WHERE retweet_id IS NOT NULL AND deleted_at IS NULL GROUP BY retweet_id) AS shared
+				WHERE tweets.id = shared.retweet_id`,
+			`UPDATE tweets SET quote_count = shared.n
+				FROM (SELECT quoted_tweet_id, COUNT(*) AS n FROM tweets
+					WHERE quoted_tweet_id IS NOT NULL AND deleted_at IS NULL GROUP BY quoted_tweet_id) AS shared
+				WHERE tweets.id = shared.quoted_tweet_id`,
+		}
+		for _, statement := range statements {
+			if err := tx.Exec(statement).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Seed creates the permissions and built-in roles the API relies on, gives
+// every user without a role the default one and, when adminUsername is set,
+// makes that user an admin.
+func Seed(db *gorm.DB, adminUsername string) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		permissions := make(map[string]Permission, len(Permissions))
+		for _, name := range Permissions {
+			permission := Permission{Id: uuid.New(), Name: name}
+			if err := tx.Where(Permission{Name: name}).FirstOrCreate(&permission).Error; err != nil {
+				return err
+			}
+			permissions[name] = permission
+		}
+
+		roles := make(map[string]Role, len(BuiltInRoles))
+		for name, names := range BuiltInRoles {
+			role := Role{Id: uuid.New(), Name: name, BuiltIn: true}
+			if err := tx.Where(Role{Name: name}).FirstOrCreate(&role).Error; err != nil {
+				return err
+			}
+
+			granted := make([]Permission, 0, len(names))
+			for _, permission := range names {
+				granted = append(granted, permissions[permission])
+			}
+			if err := tx.Model(&role).Association("Permissions").Replace(granted); err != nil {
+				return err
+			}
+			roles[name] = role
+		}
+
+		err := tx.Exec(`INSERT INTO user_roles (user_id, role_id, created_at)
+			SELECT u.id, ?, NOW() FROM users u
+			WHERE NOT EXISTS (SELECT 1 FROM user_roles ur WHERE ur.user_id = u.id)`,
+			roles[RoleUser].Id).Error
+		if err != nil {
+			return err
+		}
+
+		if adminUsername == "" {
+			return nil
+		}
+
+		return tx.Exec(`INSERT INTO user_roles (user_id, role_id, created_at)
+			SELECT u.id, ?, NOW() FROM users u
+			WHERE u.username = ? AND u.deleted_at IS NULL
+			ON CONFLICT DO NOTHING`,
+			roles[RoleAdmin].Id, adminUsername).Error
+	})
 }
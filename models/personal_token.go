@@ -0,0 +1,53 @@
+package models
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+const (
+	ScopeRead         = "read"
+	ScopeTweetsWrite  = "tweets:write"
+	ScopeLikesWrite   = "likes:write"
+	ScopeFollowsWrite = "follows:write"
+)
+
+// Scopes lists what a personal access token can be granted. Endpoints that
+// do not name one of these, such as account settings, reject such tokens.
+var Scopes = []string{
+	ScopeRead,
+	ScopeTweetsWrite,
+	ScopeLikesWrite,
+	ScopeFollowsWrite,
+}
+
+// PersonalAccessToken is a long-lived token for bots and integrations. Only
+// the hash is stored; Prefix is kept so users can tell their tokens apart.
+type PersonalAccessToken struct {
+	Id         uuid.UUID  `gorm:"primary_key; type:uuid" json:"id"`
+	UserID     uuid.UUID  `gorm:"type:uuid; not null; index" json:"user_id"`
+	Name       string     `gorm:"size:255; not null" json:"name"`
+	Prefix     string     `gorm:"size:16; not null" json:"prefix"`
+	TokenHash  string     `gorm:"size:64; not null; uniqueIndex" json:"-"`
+	Scopes     []string   `gorm:"serializer:json; not null" json:"scopes"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	RevokedAt  *time.Time `json:"revoked_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+type CreatePersonalToken struct {
+	Name      string     `json:"name" binding:"required"`
+	Scopes    []string   `json:"scopes" binding:"required"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+type CreatePersonalTokenResponse struct {
+	// Token is only returned here and cannot be retrieved again.
+	Token string              `json:"token"`
+	Info  PersonalAccessToken `json:"info"`
+}
+
+type GetAllPersonalTokensResponse struct {
+	Tokens []PersonalAccessToken `json:"tokens"`
+}
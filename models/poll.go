@@ -0,0 +1,74 @@
+package models
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+// Limits of polls, the same ones Twitter applies.
+const (
+	PollMinOptions      = 2
+	PollMaxOptions      = 4
+	PollOptionMaxLength = 25
+	PollMinDuration     = 5 * time.Minute
+	PollMaxDuration     = 7 * 24 * time.Hour
+)
+
+// Poll is attached to a tweet and takes votes until EndsAt. VoteCount and
+// the vote counts of its options cache the number of votes, so results are
+// read without counting them.
+type Poll struct {
+	Id        uuid.UUID    `gorm:"primary_key; type:uuid" json:"id"`
+	TweetID   uuid.UUID    `gorm:"type:uuid; not null; uniqueIndex" json:"tweet_id"`
+	EndsAt    time.Time    `gorm:"not null" json:"ends_at"`
+	VoteCount int64        `gorm:"not null; default:0" json:"vote_count"`
+	Options   []PollOption `gorm:"foreignKey:PollID" json:"options"`
+	CreatedAt time.Time    `json:"created_at"`
+}
+
+type PollOption struct {
+	Id        uuid.UUID `gorm:"primary_key; type:uuid" json:"id"`
+	PollID    uuid.UUID `gorm:"type:uuid; not null; uniqueIndex:idx_poll_options_position" json:"poll_id"`
+	Position  int       `gorm:"not null; uniqueIndex:idx_poll_options_position" json:"position"`
+	Label     string    `gorm:"size:100; not null" json:"label"`
+	VoteCount int64     `gorm:"not null; default:0" json:"vote_count"`
+}
+
+// PollVote is a user's vote in a poll. A user votes once per poll.
+type PollVote struct {
+	Id        uuid.UUID `gorm:"primary_key; type:uuid"`
+	PollID    uuid.UUID `gorm:"type:uuid; not null; uniqueIndex:idx_poll_votes_user"`
+	UserID    uuid.UUID `gorm:"type:uuid; not null; uniqueIndex:idx_poll_votes_user; index"`
+	OptionID  uuid.UUID `gorm:"type:uuid; not null"`
+	CreatedAt time.Time
+}
+
+// CreatePoll is a poll to attach to a new tweet. Its duration starts when
+// the tweet is published.
+type CreatePoll struct {
+	Options         []string `json:"options"`
+	DurationMinutes int      `json:"duration_minutes"`
+}
+
+type PollVoteRequest struct {
+	OptionID uuid.UUID `json:"option_id"`
+}
+
+// PollView is how the API presents a poll. Vote counts are only included
+// once the viewer has voted or the poll has closed.
+type PollView struct {
+	Id            uuid.UUID        `json:"id"`
+	EndsAt        time.Time        `json:"ends_at"`
+	Closed        bool             `json:"closed"`
+	Options       []PollOptionView `json:"options"`
+	VoteCount     *int64           `json:"vote_count,omitempty"`
+	VotedOptionID *uuid.UUID       `json:"voted_option_id,omitempty"`
+}
+
+type PollOptionView struct {
+	Id         uuid.UUID `json:"id"`
+	Position   int       `json:"position"`
+	Label      string    `json:"label"`
+	VoteCount  *int64    `json:"vote_count,omitempty"`
+	Percentage *float64  `json:"percentage,omitempty"`
+}
@@ -0,0 +1,72 @@
+package models
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+const (
+	PermTweetsWrite    = "tweets:write"
+	PermTweetsModerate = "tweets:moderate"
+	PermUsersModerate  = "users:moderate"
+	PermRolesManage    = "roles:manage"
+	PermAuditRead      = "audit:read"
+)
+
+const (
+	RoleUser      = "user"
+	RoleModerator = "moderator"
+	RoleAdmin     = "admin"
+)
+
+// Permissions lists every permission the API checks. Custom roles may only
+// be built from these.
+var Permissions = []string{
+	PermTweetsWrite,
+	PermTweetsModerate,
+	PermUsersModerate,
+	PermRolesManage,
+	PermAuditRead,
+}
+
+// BuiltInRoles are created on startup and kept in sync with this map.
+var BuiltInRoles = map[string][]string{
+	RoleUser:      {PermTweetsWrite},
+	RoleModerator: {PermTweetsWrite, PermTweetsModerate, PermUsersModerate, PermAuditRead},
+	RoleAdmin:     {PermTweetsWrite, PermTweetsModerate, PermUsersModerate, PermRolesManage, PermAuditRead},
+}
+
+type Permission struct {
+	Id   uuid.UUID `gorm:"primary_key; type:uuid"`
+	Name string    `gorm:"size:64; not null; uniqueIndex"`
+}
+
+type Role struct {
+	Id          uuid.UUID    `gorm:"primary_key; type:uuid"`
+	Name        string       `gorm:"size:64; not null; uniqueIndex"`
+	Description *string      `gorm:"size:255"`
+	BuiltIn     bool         `gorm:"not null; default:false"`
+	Permissions []Permission `gorm:"many2many:role_permissions"`
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+type UserRole struct {
+	UserID    uuid.UUID `gorm:"type:uuid; primaryKey"`
+	RoleID    uuid.UUID `gorm:"type:uuid; primaryKey; index"`
+	CreatedAt time.Time
+}
+
+type CreateRole struct {
+	Name        string   `json:"name" binding:"required"`
+	Description *string  `json:"description"`
+	Permissions []string `json:"permissions" binding:"required"`
+}
+
+type AssignRole struct {
+	Role string `json:"role" binding:"required"`
+}
+
+type GetAllRolesResponse struct {
+	Roles []Role `json:"roles"`
+}
@@ -0,0 +1,42 @@
+package models
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+// ScheduledTweet is a tweet waiting to be published at PublishAt. It is not
+// a tweet until then: once published, TweetID points at the tweet that was
+// created from it. Its entities are extracted from Content on publishing.
+// A tweet that could not be published keeps FailedAt and LastError and is
+// not retried until it is rescheduled.
+type ScheduledTweet struct {
+	Id          uuid.UUID   `gorm:"primary_key; type:uuid" json:"id"`
+	UserID      uuid.UUID   `gorm:"type:uuid; not null; index" json:"user_id"`
+	Content     string      `gorm:"type:text; not null" json:"content"`
+	ImagePath   *string     `gorm:"size:255" json:"image_path"`
+	VideoPath   *string     `gorm:"size:255" json:"video_path"`
+	Poll        *CreatePoll `gorm:"type:jsonb; serializer:json" json:"poll"`
+	PublishAt   time.Time   `gorm:"not null; index" json:"publish_at"`
+	TweetID     *uuid.UUID  `gorm:"type:uuid" json:"tweet_id,omitempty"`
+	PublishedAt *time.Time  `json:"published_at,omitempty"`
+	FailedAt    *time.Time  `json:"failed_at,omitempty"`
+	LastError   *string     `gorm:"type:text" json:"last_error,omitempty"`
+	CreatedAt   time.Time   `json:"created_at"`
+	UpdatedAt   time.Time   `json:"updated_at"`
+}
+
+type GetScheduledTweetsRequest struct {
+	UserID uuid.UUID
+	Page   uint64
+	Limit  uint64
+}
+
+type GetScheduledTweetsResponse struct {
+	Tweets []ScheduledTweet `json:"tweets"`
+	Count  int64            `json:"count"`
+}
+
+type RescheduleTweet struct {
+	PublishAt time.Time `json:"publish_at"`
+}
@@ -0,0 +1,50 @@
+package models
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+// Session is one login of a user on a device. Its Id is the FamilyID of the
+// refresh tokens issued for it and the sid claim of its access tokens, so
+// revoking a session ends both.
+type Session struct {
+	Id         uuid.UUID  `gorm:"primary_key; type:uuid" json:"id"`
+	UserID     uuid.UUID  `gorm:"type:uuid; not null; index" json:"-"`
+	UserAgent  string     `gorm:"size:512; not null" json:"user_agent"`
+	IP         string     `gorm:"size:64; not null" json:"ip"`
+	LastSeenAt time.Time  `gorm:"not null" json:"last_seen_at"`
+	RevokedAt  *time.Time `json:"-"`
+	CreatedAt  time.Time  `json:"created_at"`
+	// Current marks the session the request was made with.
+	Current bool `gorm:"-" json:"current"`
+}
+
+// LoginEvent is an entry in the login history of a user. Devices are told
+// apart by their user agent, so NewDevice is set the first time a user logs
+// in with a user agent they have not used before.
+type LoginEvent struct {
+	Id         uuid.UUID `gorm:"primary_key; type:uuid" json:"id"`
+	UserID     uuid.UUID `gorm:"type:uuid; not null; index:idx_login_events_device" json:"-"`
+	SessionID  uuid.UUID `gorm:"type:uuid; not null" json:"session_id"`
+	DeviceHash string    `gorm:"size:64; not null; index:idx_login_events_device" json:"-"`
+	UserAgent  string    `gorm:"size:512; not null" json:"user_agent"`
+	IP         string    `gorm:"size:64; not null" json:"ip"`
+	NewDevice  bool      `gorm:"not null" json:"new_device"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type GetAllSessionsResponse struct {
+	Sessions []Session `json:"sessions"`
+}
+
+type GetLoginHistoryRequest struct {
+	Page   uint64    `json:"page"`
+	Limit  uint64    `json:"limit"`
+	UserID uuid.UUID `json:"user_id"`
+}
+
+type GetLoginHistoryResponse struct {
+	Logins []LoginEvent `json:"logins"`
+	Count  int64        `json:"count"`
+}
@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// SigningKey is a key pair used to sign JWTs. A key signs new tokens until
+// SignUntil and is published for verification until VerifyUntil, so tokens
+// it signed stay valid after the next key has taken over.
+type SigningKey struct {
+	Kid         string    `gorm:"primary_key; size:64"`
+	Algorithm   string    `gorm:"size:16; not null"`
+	PrivateKey  []byte    `gorm:"not null"`
+	SignUntil   time.Time `gorm:"not null"`
+	VerifyUntil time.Time `gorm:"not null; index"`
+	CreatedAt   time.Time
+}
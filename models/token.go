@@ -0,0 +1,44 @@
+package models
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+// RefreshToken is a single link in a rotation chain. All tokens issued from
+// one login share a FamilyID so a replayed token can revoke the whole chain.
+type RefreshToken struct {
+	Id           uuid.UUID `gorm:"primary_key; type:uuid"`
+	UserID       uuid.UUID `gorm:"type:uuid; not null; index"`
+	FamilyID     uuid.UUID `gorm:"type:uuid; not null; index"`
+	TokenHash    string    `gorm:"size:64; not null; uniqueIndex"`
+	ExpiresAt    time.Time `gorm:"not null"`
+	RevokedAt    *time.Time
+	ReplacedByID *uuid.UUID `gorm:"type:uuid"`
+	CreatedAt    time.Time
+}
+
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+type LogoutRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+const (
+	TokenPurposePasswordReset     = "password_reset"
+	TokenPurposeEmailVerification = "email_verification"
+)
+
+// UserToken is a single-use secret sent to a user out of band, e.g. a
+// password reset link. Only the hash of the token is stored.
+type UserToken struct {
+	Id        uuid.UUID `gorm:"primary_key; type:uuid"`
+	UserID    uuid.UUID `gorm:"type:uuid; not null; index"`
+	Purpose   string    `gorm:"size:32; not null"`
+	TokenHash string    `gorm:"size:64; not null; uniqueIndex"`
+	ExpiresAt time.Time `gorm:"not null"`
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
@@ -3,26 +3,132 @@ package models
 import (
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"project/etc/tweettext"
 	"time"
 )
 
+// Kinds of tweets. A retweet shares another tweet as is, a quote shares it
+// with commentary and a reply answers it within its conversation.
+const (
+	TweetKindOriginal = "original"
+	TweetKindRetweet  = "retweet"
+	TweetKindQuote    = "quote"
+	TweetKindReply    = "reply"
+)
+
 type Tweet struct {
-	Id        uuid.UUID  `gorm:"primary_key; type:uuid"`
-	UserID    uuid.UUID  `gorm:"type:uuid; not null; foreign_key; references: user_id; constraint: OnUpdate:CASCADE, OnDelete: SET NULL"`
-	Content   string     `gorm:"type:text; not null"`
-	ImagePath *string    `gorm:"size:255"`
-	VideoPath *string    `gorm:"size:255"`
-	RetweetID *uuid.UUID `gorm:"type:uuid"`
+	Id      uuid.UUID `gorm:"primary_key; type:uuid"`
+	UserID  uuid.UUID `gorm:"type:uuid; not null; foreign_key; references: user_id; constraint: OnUpdate:CASCADE, OnDelete: SET NULL"`
+	Content string    `gorm:"type:text; not null"`
+	// Entities are extracted from Content whenever it is written.
+	Entities  *tweettext.Entities `gorm:"type:jsonb; serializer:json" json:"entities"`
+	ImagePath *string             `gorm:"size:255"`
+	VideoPath *string             `gorm:"size:255"`
+	Kind      string              `gorm:"size:16; not null; default:original; index" json:"kind"`
+	// RetweetID is only set on retweets and QuotedTweetID only on quotes.
+	RetweetID     *uuid.UUID `gorm:"type:uuid; index"`
+	QuotedTweetID *uuid.UUID `gorm:"type:uuid; index" json:"quoted_tweet_id"`
+	// InReplyToID is set on replies. ConversationID is the id of the tweet
+	// that started the thread, which is the tweet itself for anything that
+	// is not a reply.
+	InReplyToID    *uuid.UUID `gorm:"type:uuid; index" json:"in_reply_to_id"`
+	ConversationID uuid.UUID  `gorm:"type:uuid; index" json:"conversation_id"`
+	// The counters only count replies, retweets and quotes that have not
+	// been deleted.
+	ReplyCount   int64 `gorm:"not null; default:0" json:"reply_count"`
+	RetweetCount int64 `gorm:"not null; default:0" json:"retweet_count"`
+	QuoteCount   int64 `gorm:"not null; default:0" json:"quote_count"`
+	// EditCount counts the edits kept as revisions; EditedAt is the time of
+	// the latest one.
+	EditCount int        `gorm:"not null; default:0" json:"edit_count"`
+	EditedAt  *time.Time `json:"edited_at"`
+	// Poll is only read when the tweet is created, to attach a poll to it.
+	Poll      *CreatePoll `gorm:"-" json:"-"`
 	CreatedAt time.Time
 	UpdatedAt time.Time
 	DeletedAt gorm.DeletedAt `gorm:"index"`
 }
 
+// TweetRevision is a version of a tweet that an edit replaced. Version 1 is
+// the tweet as first posted; CreatedAt is when the version was posted.
+type TweetRevision struct {
+	Id        uuid.UUID `gorm:"primary_key; type:uuid" json:"-"`
+	TweetID   uuid.UUID `gorm:"type:uuid; not null; uniqueIndex:idx_tweet_revisions_version" json:"tweet_id"`
+	Version   int       `gorm:"not null; uniqueIndex:idx_tweet_revisions_version" json:"version"`
+	Content   string    `gorm:"type:text; not null" json:"content"`
+	ImagePath *string   `gorm:"size:255" json:"image_path"`
+	VideoPath *string   `gorm:"size:255" json:"video_path"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// GetTweetHistoryResponse lists every version of a tweet, oldest first. The
+// last one is the current version.
+type GetTweetHistoryResponse struct {
+	Revisions []TweetRevision `json:"revisions"`
+	Count     int             `json:"count"`
+}
+
+// TweetView is how the API presents a tweet: with its author, engagement
+// counters, the tweet it retweets or quotes and, for authenticated
+// requests, the viewer's state.
+type TweetView struct {
+	Id             uuid.UUID          `json:"id"`
+	Kind           string             `json:"kind"`
+	Content        string             `json:"content"`
+	Entities       tweettext.Entities `json:"entities"`
+	ImagePath      *string            `json:"image_path"`
+	VideoPath      *string            `json:"video_path"`
+	InReplyToID    *uuid.UUID         `json:"in_reply_to_id"`
+	ConversationID uuid.UUID          `json:"conversation_id"`
+	// RetweetID and QuotedTweetID stay set when the referenced tweet is not
+	// embedded because the viewer may not see it.
+	RetweetID     *uuid.UUID   `json:"retweet_id"`
+	QuotedTweetID *uuid.UUID   `json:"quoted_tweet_id"`
+	Author        *TweetAuthor `json:"author"`
+	LikeCount     int64        `json:"like_count"`
+	ReplyCount    int64        `json:"reply_count"`
+	RetweetCount  int64        `json:"retweet_count"`
+	QuoteCount    int64        `json:"quote_count"`
+	EditCount     int          `json:"edit_count"`
+	EditedAt      *time.Time   `json:"edited_at"`
+	CreatedAt     time.Time    `json:"created_at"`
+	UpdatedAt     time.Time    `json:"updated_at"`
+
+	// Retweeted and Quoted embed the referenced tweet, one level deep.
+	Retweeted *TweetView `json:"retweeted,omitempty"`
+	Quoted    *TweetView `json:"quoted,omitempty"`
+
+	// Viewer-specific fields, only set for authenticated requests.
+	LikedByMe     *bool `json:"liked_by_me,omitempty"`
+	RetweetedByMe *bool `json:"retweeted_by_me,omitempty"`
+
+	Poll *PollView `json:"poll,omitempty"`
+}
+
+// TweetAuthor is the public profile of a tweet's author.
+type TweetAuthor struct {
+	Id           uuid.UUID `json:"id"`
+	Name         string    `json:"name"`
+	Username     string    `json:"username"`
+	ProfileImage *string   `json:"profile_image"`
+}
+
 type GetAllTweetsRequest struct {
 	Page   uint64 `json:"page"`
 	Limit  uint64 `json:"limit"`
 	UserID string `json:"user_id"`
 	Search string `json:"search"`
+	// InReplyToID limits the result to direct replies, oldest first.
+	InReplyToID string `json:"in_reply_to_id"`
+	// Hashtag limits the result to tweets using the normalized hashtag,
+	// newest first.
+	Hashtag string `json:"hashtag"`
+	// MentionedUserID limits the result to tweets mentioning the user,
+	// newest first.
+	MentionedUserID uuid.UUID `json:"-"`
+	// ViewerID hides tweets from users blocked either way and, outside of a
+	// single user's tweets, muted by the viewer.
+	ViewerID uuid.UUID `json:"-"`
 }
 
 type GetAllTweetsResponse struct {
@@ -30,9 +136,33 @@ type GetAllTweetsResponse struct {
 	Count  int64   `json:"count"`
 }
 
+type GetTweetViewsResponse struct {
+	Tweets []TweetView `json:"tweets"`
+	Count  int64       `json:"count"`
+}
+
 type CreateUpdateTweet struct {
-	Content   string     `json:"content"`
-	ImagePath *string    `json:"image_path"`
-	VideoPath *string    `json:"video_path"`
-	RetweetID *uuid.UUID `json:"retweet_id"`
+	Content   string  `json:"content"`
+	ImagePath *string `json:"image_path"`
+	VideoPath *string `json:"video_path"`
+	// PublishAt schedules a new tweet instead of posting it right away.
+	PublishAt *time.Time `json:"publish_at"`
+	// Poll attaches a poll to a new tweet or reply.
+	Poll *CreatePoll `json:"poll"`
+}
+
+// ConversationTweet is a tweet within a conversation. Deleted and hidden
+// tweets are kept as placeholders without content so their replies stay
+// reachable.
+type ConversationTweet struct {
+	TweetView
+	Depth       int                  `json:"depth"`
+	Placeholder string               `json:"placeholder,omitempty"`
+	Replies     []*ConversationTweet `json:"replies,omitempty"`
+}
+
+type GetConversationResponse struct {
+	ConversationID uuid.UUID            `json:"conversation_id"`
+	Tweets         []*ConversationTweet `json:"tweets"`
+	Count          int                  `json:"count"`
 }
@@ -0,0 +1,45 @@
+package models
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+type RecoveryCode struct {
+	Id        uuid.UUID `gorm:"primary_key; type:uuid"`
+	UserID    uuid.UUID `gorm:"type:uuid; not null; index"`
+	CodeHash  string    `gorm:"size:64; not null"`
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
+
+type TwoFactorEnrollResponse struct {
+	Secret     string `json:"secret"`
+	OtpauthURI string `json:"otpauth_uri"`
+}
+
+type TwoFactorCodeRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+type TwoFactorConfirmResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+	// Login is only set when enrollment was done with an enrollment token,
+	// so the user can continue without logging in again.
+	Login *LoginResponse `json:"login,omitempty"`
+}
+
+type TwoFactorDisableRequest struct {
+	Password string `json:"password" binding:"required"`
+	Code     string `json:"code" binding:"required"`
+}
+
+type TwoFactorLoginRequest struct {
+	ChallengeToken string `json:"challenge_token" binding:"required"`
+	Code           string `json:"code"`
+	RecoveryCode   string `json:"recovery_code"`
+}
+
+type TwoFactorRequirement struct {
+	Required bool `json:"required"`
+}
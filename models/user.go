@@ -11,11 +11,36 @@ type User struct {
 	Name         string    `gorm:"size:255; not null"`
 	Bio          *string   `gorm:"size:255;"`
 	Username     string    `gorm:"size:255; unique; not null; uniqueIndex:idx_username_deleted_at"`
-	Password     string    `gorm:"size:255; not null"`
+	Password     string    `gorm:"size:255; not null" json:"-"`
 	ProfileImage *string   `gorm:"size:255"`
-	CreatedAt    time.Time
-	UpdatedAt    time.Time
-	DeletedAt    gorm.DeletedAt `gorm:"index; uniqueIndex:idx_username_deleted_at"`
+	// Email is stored lower-cased, which makes the unique index case-insensitive.
+	// It is nil only for accounts created before e-mail addresses were required.
+	Email           *string    `gorm:"size:255; uniqueIndex" json:"-"`
+	EmailVerifiedAt *time.Time `json:"-"`
+	// TOTPSecret is set as soon as enrollment starts; two-factor login is
+	// only enforced once TOTPEnabledAt is set by a confirmed code.
+	TOTPSecret        *string    `gorm:"size:64" json:"-"`
+	TOTPEnabledAt     *time.Time `json:"-"`
+	TOTPLastStep      int64      `gorm:"not null; default:0" json:"-"`
+	TwoFactorRequired bool       `gorm:"not null; default:false" json:"-"`
+	// FailedLogins counts consecutive failed logins and is reset by a
+	// successful one. Past a threshold the account is locked until LockedUntil.
+	FailedLogins int        `gorm:"not null; default:0" json:"-"`
+	LockedUntil  *time.Time `json:"-"`
+	// DeactivatedAt hides the account and its content. Unless the user logs
+	// in again, the account is purged once the deletion grace period is over.
+	// DeactivatedBy is set when a moderator deactivated the account, which
+	// the user cannot undo by logging in.
+	DeactivatedAt *time.Time `gorm:"index" json:"-"`
+	DeactivatedBy *uuid.UUID `gorm:"type:uuid" json:"-"`
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+	DeletedAt     gorm.DeletedAt `gorm:"index; uniqueIndex:idx_username_deleted_at"`
+
+	// Viewer-specific fields, only set for authenticated requests.
+	Following   *bool `gorm:"-" json:"following,omitempty"`
+	BlockedByMe *bool `gorm:"-" json:"blocked_by_me,omitempty"`
+	MutedByMe   *bool `gorm:"-" json:"muted_by_me,omitempty"`
 }
 
 type GetAllUsersRequest struct {
@@ -24,6 +49,8 @@ type GetAllUsersRequest struct {
 	Search    string    `json:"search"`
 	Followers uuid.UUID `json:"id_followers"`
 	Following uuid.UUID `json:"id_following"`
+	// Retweeted lists the users who retweeted the tweet, latest first.
+	Retweeted uuid.UUID `json:"id_retweeted"`
 }
 
 type GetAllUsersResponse struct {
@@ -35,10 +62,15 @@ type CreateUser struct {
 	Name         string  `json:"name"`
 	Bio          *string `json:"bio"`
 	Username     string  `json:"username"`
+	Email        string  `json:"email" binding:"required,email"`
 	Password     string  `json:"password"`
 	ProfileImage *string `json:"profileImage"`
 }
 
+type VerifyEmailRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
 type UpdateUser struct {
 	Name         string  `json:"name"`
 	Bio          *string `json:"bio"`
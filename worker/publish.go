@@ -0,0 +1,54 @@
+package worker
+
+import (
+	"context"
+	"log"
+	"project/config"
+	"project/database"
+	"time"
+)
+
+const publishBatchSize = 100
+
+// Publisher publishes scheduled tweets once they are due.
+type Publisher struct {
+	store database.IStore
+	cfg   config.Config
+}
+
+func NewPublisher(store database.IStore, cfg config.Config) *Publisher {
+	return &Publisher{store: store, cfg: cfg}
+}
+
+// Run publishes due tweets right away and then every ScheduleInterval until
+// ctx is cancelled.
+func (p *Publisher) Run(ctx context.Context) {
+	ticker := time.NewTicker(p.cfg.ScheduleInterval)
+	defer ticker.Stop()
+
+	for {
+		p.PublishDue(time.Now())
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// PublishDue publishes every tweet scheduled at or before now. A tweet that
+// fails is marked as failed by the store; other failures are logged and
+// retried on the next run.
+func (p *Publisher) PublishDue(now time.Time) {
+	for {
+		published, err := p.store.ScheduledTweet().PublishDue(now, publishBatchSize)
+		if err != nil {
+			log.Printf("Failed to publish scheduled tweets: %v", err)
+			return
+		}
+		if published < publishBatchSize {
+			return
+		}
+	}
+}
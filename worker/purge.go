@@ -0,0 +1,98 @@
+// Package worker holds the background jobs that run next to the API.
+package worker
+
+import (
+	"context"
+	"log"
+	"os"
+	"path"
+	"path/filepath"
+	"project/config"
+	"project/database"
+	"strings"
+	"time"
+)
+
+const (
+	purgeInterval  = time.Hour
+	purgeBatchSize = 100
+	// mediaURLPrefix is where the router serves MediaDir from.
+	mediaURLPrefix = "/images/"
+)
+
+// Purger permanently deletes accounts whose deletion grace period is over.
+type Purger struct {
+	store database.IStore
+	cfg   config.Config
+}
+
+func NewPurger(store database.IStore, cfg config.Config) *Purger {
+	return &Purger{store: store, cfg: cfg}
+}
+
+// Run purges due accounts right away and then every purgeInterval until ctx
+// is cancelled.
+func (p *Purger) Run(ctx context.Context) {
+	ticker := time.NewTicker(purgeInterval)
+	defer ticker.Stop()
+
+	for {
+		p.PurgeDue(time.Now())
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// PurgeDue purges every account deactivated longer than the grace period
+// before now. Failures are logged and retried on the next run.
+func (p *Purger) PurgeDue(now time.Time) {
+	cutoff := now.Add(-p.cfg.AccountDeletionGrace)
+	for {
+		ids, err := p.store.User().GetDeactivatedBefore(cutoff, purgeBatchSize)
+		if err != nil {
+			log.Printf("Failed to list accounts to purge: %v", err)
+			return
+		}
+
+		purged := 0
+		for _, id := range ids {
+			media, err := p.store.User().Purge(id)
+			if err != nil {
+				log.Printf("Failed to purge user %s: %v", id, err)
+				continue
+			}
+			p.removeMedia(media)
+			purged++
+		}
+
+		// Stop when the batch was the last one, or when nothing in it could
+		// be purged, so failing accounts are not retried in a loop.
+		if len(ids) < purgeBatchSize || purged == 0 {
+			return
+		}
+	}
+}
+
+// removeMedia deletes the files behind media paths that point into the
+// media directory. Other paths, e.g. external URLs, are left alone.
+func (p *Purger) removeMedia(media []string) {
+	for _, mediaPath := range media {
+		if !strings.HasPrefix(mediaPath, mediaURLPrefix) {
+			continue
+		}
+
+		name := path.Clean(strings.TrimPrefix(mediaPath, mediaURLPrefix))
+		if name == "." || strings.HasPrefix(name, "../") || name == ".." {
+			continue
+		}
+
+		file := filepath.Join(p.cfg.MediaDir, filepath.FromSlash(name))
+		if err := os.Remove(file); err != nil && !os.IsNotExist(err) {
+			log.Printf("Failed to remove media %s: %v", file, err)
+		}
+	}
+}
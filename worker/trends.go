@@ -0,0 +1,43 @@
+package worker
+
+import (
+	"context"
+	"log"
+	"project/config"
+	"project/database"
+	"time"
+)
+
+// maxTrends is how many hashtags are kept ranked.
+const maxTrends = 50
+
+// Trender recomputes the trending hashtags in the background, so requests
+// only read the latest ranking.
+type Trender struct {
+	store database.IStore
+	cfg   config.Config
+}
+
+func NewTrender(store database.IStore, cfg config.Config) *Trender {
+	return &Trender{store: store, cfg: cfg}
+}
+
+// Run recomputes the trends right away and then every TrendInterval until
+// ctx is cancelled.
+func (t *Trender) Run(ctx context.Context) {
+	ticker := time.NewTicker(t.cfg.TrendInterval)
+	defer ticker.Stop()
+
+	for {
+		err := t.store.Hashtag().ComputeTrends(time.Now(), t.cfg.TrendWindow, t.cfg.TrendHalfLife, maxTrends)
+		if err != nil {
+			log.Printf("Failed to compute trends: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}